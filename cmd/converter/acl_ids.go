@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"converter/model"
+)
+
+// aclAllocation is one row of the old→new ACL number table.
+type aclAllocation struct {
+	ref    string
+	newID  int
+	reason string
+}
+
+// loadACLMapping reads an explicit ACL number mapping: one "<old> <new>" or
+// "<old>=<new>" pair per line, where <old> is a source ACL number or name.
+// Empty lines and lines starting with "#" are ignored.
+func loadACLMapping(path string) (map[string]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(strings.Replace(line, "=", " ", 1))
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"<old> <new>\", got %q", path, lineNo, line)
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%s:%d: invalid ACL number %q", path, lineNo, fields[1])
+		}
+		if _, ok := result[fields[0]]; ok {
+			return nil, fmt.Errorf("%s:%d: ACL %s mapped twice", path, lineNo, fields[0])
+		}
+		result[fields[0]] = id
+	}
+	return result, scanner.Err()
+}
+
+// allocateACLIDs renumbers ACLs for the target vendor. Explicit mappings are
+// applied first, then every numbered ACL gets its default number if that is
+// free and within the vendor range for its type, otherwise the lowest free
+// number of the range. Named ACLs keep their name; for Huawei, which numbers
+// every ACL, they keep the number of the same-named ACL on the running device
+// or get the highest free number of the range, as VRP does. References to
+// undefined numbered ACLs get their default number, with the type implied by
+// the source number, when it is free. All references (interfaces, lines, NAT,
+// route-maps) are rewritten from the same table, and the generators use these
+// numbers as they are.
+//
+// running is the config already on the target device, or nil. Its ACL numbers
+// are never handed out as free numbers; an ACL may still take its default
+// number when the running ACL there has the same name, since that is where an
+// earlier conversion put it.
+func allocateACLIDs(cfg *model.Config, vendor string, explicit map[string]int, running *model.Config) ([]aclAllocation, error) {
+	used := make(map[int]string)
+	assigned := make(map[int]int)
+	rows := make([]*aclAllocation, len(cfg.ACLs))
+	deployed := make(map[int]model.ACL)
+	if running != nil {
+		for _, acl := range running.ACLs {
+			if acl.ID != 0 {
+				deployed[acl.ID] = acl
+			}
+		}
+	}
+	taken := func(id int) bool {
+		_, inUse := used[id]
+		_, onDevice := deployed[id]
+		return inUse || onDevice
+	}
+	// conflict explains why an ACL named name cannot take number id.
+	conflict := func(id int, name string) string {
+		if other, ok := used[id]; ok {
+			return "collision with " + other
+		}
+		if acl, ok := deployed[id]; ok && acl.Name != name {
+			return "collision with running ACL " + acl.Ref()
+		}
+		return ""
+	}
+
+	for ref := range explicit {
+		if _, ok := cfg.FindACL(ref); !ok {
+			return nil, fmt.Errorf("ACL mapping: ACL %s not found in the source config", ref)
+		}
+	}
+	for i, acl := range cfg.ACLs {
+		id, ok := explicit[acl.Ref()]
+		if !ok {
+			continue
+		}
+		if !inACLRanges(id, model.ACLIDRanges(vendor, acl.Type)) {
+			return nil, fmt.Errorf("ACL mapping: %s -> %d is outside the %s range for %s ACLs", acl.Ref(), id, vendor, acl.Type)
+		}
+		if other, ok := used[id]; ok {
+			return nil, fmt.Errorf("ACL mapping: %s and %s both map to %d", other, acl.Ref(), id)
+		}
+		used[id] = acl.Ref()
+		assigned[i] = id
+		rows[i] = &aclAllocation{ref: acl.Ref(), newID: id, reason: "explicit"}
+	}
+
+	for i, acl := range cfg.ACLs {
+		if _, ok := assigned[i]; ok || acl.ID == 0 {
+			continue
+		}
+		ranges := model.ACLIDRanges(vendor, acl.Type)
+		id := model.DefaultACLID(vendor, acl.ID, acl.Type)
+		reason := conflict(id, acl.Name)
+		if reason == "" && !inACLRanges(id, ranges) {
+			reason = fmt.Sprintf("%d is outside the %s range", id, vendor)
+		}
+		if reason != "" {
+			id = freeACLID(ranges, taken)
+			if id == 0 {
+				return nil, fmt.Errorf("no free %s ACL number left for %s", vendor, acl.Ref())
+			}
+		}
+		used[id] = acl.Ref()
+		assigned[i] = id
+		rows[i] = &aclAllocation{ref: acl.Ref(), newID: id, reason: reason}
+	}
+
+	if vendor == "huawei" {
+		for i, acl := range cfg.ACLs {
+			if _, ok := assigned[i]; ok || acl.ID != 0 {
+				continue
+			}
+			ranges := model.ACLIDRanges(vendor, acl.Type)
+			id := 0
+			if running != nil {
+				if existing, ok := running.FindACL(acl.Name); ok && existing.Name == acl.Name &&
+					inACLRanges(existing.ID, ranges) && conflict(existing.ID, acl.Name) == "" {
+					id = existing.ID
+				}
+			}
+			reason := ""
+			if id == 0 {
+				id = lastFreeACLID(ranges, taken)
+				reason = "named"
+			}
+			if id == 0 {
+				return nil, fmt.Errorf("no free %s ACL number left for %s", vendor, acl.Ref())
+			}
+			used[id] = acl.Ref()
+			assigned[i] = id
+			rows[i] = &aclAllocation{ref: acl.Ref(), newID: id, reason: reason}
+		}
+	}
+
+	renames := make(map[string]string)
+	var dangling []aclAllocation
+	for _, ref := range undefinedACLRefs(cfg) {
+		old, _ := strconv.Atoi(ref)
+		aclType := aclTypeByNumber(cfg.DeviceType, old)
+		id := model.DefaultACLID(vendor, old, aclType)
+		if conflict(id, "") != "" || !inACLRanges(id, model.ACLIDRanges(vendor, aclType)) {
+			dangling = append(dangling, aclAllocation{ref: ref, newID: old, reason: "not defined, left as is"})
+			continue
+		}
+		used[id] = ref
+		renames[ref] = strconv.Itoa(id)
+		dangling = append(dangling, aclAllocation{ref: ref, newID: id, reason: "not defined"})
+	}
+	for i, id := range assigned {
+		acl := &cfg.ACLs[i]
+		if acl.Name == "" {
+			renames[strconv.Itoa(acl.ID)] = strconv.Itoa(id)
+		}
+		acl.ID = id
+	}
+	renameACLRefs(cfg, renames)
+
+	var table []aclAllocation
+	for _, row := range rows {
+		if row != nil {
+			table = append(table, *row)
+		}
+	}
+	return append(table, dangling...), nil
+}
+
+// aclTypeByNumber infers the type of an ACL from its number in the source
+// vendor's ranges: "extended" or "advanced" for the extended ranges, "" for
+// standard (basic) ones.
+func aclTypeByNumber(vendor string, id int) string {
+	switch {
+	case vendor == "huawei" && inACLRanges(id, model.ACLIDRanges(vendor, "advanced")):
+		return "advanced"
+	case vendor != "huawei" && inACLRanges(id, model.ACLIDRanges(vendor, "extended")):
+		return "extended"
+	}
+	return ""
+}
+
+// undefinedACLRefs lists numeric ACL references that no ACL of cfg matches, in
+// order of first use.
+func undefinedACLRefs(cfg *model.Config) []string {
+	var refs []string
+	check := func(ref string) {
+		if _, err := strconv.Atoi(ref); err != nil {
+			return
+		}
+		if _, ok := cfg.FindACL(ref); !ok && !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	for _, i := range cfg.Interfaces {
+		check(i.ACLIn)
+		check(i.ACLOut)
+	}
+	for _, l := range cfg.Lines {
+		check(l.ACLIn)
+		check(l.ACLOut)
+	}
+	for _, r := range cfg.NATRule {
+		if r.ACLName == "" && r.ACLID != 0 {
+			check(strconv.Itoa(r.ACLID))
+		}
+	}
+	for _, rm := range cfg.RouteMaps {
+		for _, e := range rm.Entries {
+			for _, ref := range e.MatchACLs {
+				check(ref)
+			}
+		}
+	}
+	return refs
+}
+
+func inACLRanges(id int, ranges [][2]int) bool {
+	for _, r := range ranges {
+		if id >= r[0] && id <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func freeACLID(ranges [][2]int, taken func(int) bool) int {
+	for _, r := range ranges {
+		for id := r[0]; id <= r[1]; id++ {
+			if !taken(id) {
+				return id
+			}
+		}
+	}
+	return 0
+}
+
+// lastFreeACLID is freeACLID searching from the top of the ranges down.
+func lastFreeACLID(ranges [][2]int, taken func(int) bool) int {
+	for i := len(ranges) - 1; i >= 0; i-- {
+		for id := ranges[i][1]; id >= ranges[i][0]; id-- {
+			if !taken(id) {
+				return id
+			}
+		}
+	}
+	return 0
+}
+
+// renameACLRefs rewrites references to numbered ACLs. Every reference is
+// looked up once against the old numbers, so chains like 10->2010, 2010->2011
+// cannot be applied twice.
+func renameACLRefs(cfg *model.Config, renames map[string]string) {
+	rename := func(ref string) string {
+		if to, ok := renames[ref]; ok {
+			return to
+		}
+		return ref
+	}
+	for i := range cfg.Interfaces {
+		cfg.Interfaces[i].ACLIn = rename(cfg.Interfaces[i].ACLIn)
+		cfg.Interfaces[i].ACLOut = rename(cfg.Interfaces[i].ACLOut)
+	}
+	for i := range cfg.Lines {
+		cfg.Lines[i].ACLIn = rename(cfg.Lines[i].ACLIn)
+		cfg.Lines[i].ACLOut = rename(cfg.Lines[i].ACLOut)
+	}
+	for i := range cfg.NATRule {
+		if cfg.NATRule[i].ACLName == "" && cfg.NATRule[i].ACLID != 0 {
+			cfg.NATRule[i].ACLID, _ = strconv.Atoi(rename(strconv.Itoa(cfg.NATRule[i].ACLID)))
+		}
+	}
+	for i := range cfg.RouteMaps {
+		for j := range cfg.RouteMaps[i].Entries {
+			refs := cfg.RouteMaps[i].Entries[j].MatchACLs
+			for k := range refs {
+				refs[k] = rename(refs[k])
+			}
+		}
+	}
+}
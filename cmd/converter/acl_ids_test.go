@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"converter/model"
+)
+
+func TestAllocateACLIDsDanglingType(t *testing.T) {
+	cfg := &model.Config{
+		DeviceType: "cisco",
+		Interfaces: []model.Interface{{Name: "Gi0/1", ACLIn: "101", ACLOut: "5"}},
+	}
+	if _, err := allocateACLIDs(cfg, "huawei", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Interfaces[0]; got.ACLIn != "3001" || got.ACLOut != "2005" {
+		t.Errorf("got references %s and %s, want 3001 and 2005", got.ACLIn, got.ACLOut)
+	}
+}
+
+func TestAllocateACLIDsAvoidsRunningACLs(t *testing.T) {
+	cfg := &model.Config{
+		DeviceType: "cisco",
+		ACLs: []model.ACL{
+			{ID: 10, Type: "standard"},
+			{ID: 20, Type: "standard"},
+			{Name: "WEB", Type: "extended"},
+			{Name: "MGMT", Type: "standard"},
+		},
+	}
+	running := &model.Config{ACLs: []model.ACL{
+		{ID: 2010, Type: "basic"},
+		{ID: 2020, Name: "OTHER", Type: "basic"},
+		{ID: 3999, Name: "USED", Type: "advanced"},
+		{ID: 2500, Name: "MGMT", Type: "basic"},
+		{ID: 2000, Type: "basic"},
+	}}
+	if _, err := allocateACLIDs(cfg, "huawei", nil, running); err != nil {
+		t.Fatal(err)
+	}
+	want := []int{2010, 2001, 3998, 2500}
+	for i, acl := range cfg.ACLs {
+		if acl.ID != want[i] {
+			t.Errorf("ACL %d: got %d, want %d", i, acl.ID, want[i])
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"converter/model"
+)
+
+// lintACLs reports shadowed, redundant and conflicting ACL rules. With prune
+// set, shadowed and redundant rules are removed together with the remarks
+// written right before them; conflicts are only reported.
+func lintACLs(cfg *model.Config, prune bool) []string {
+	var messages []string
+	for i := range cfg.ACLs {
+		acl := &cfg.ACLs[i]
+		findings := model.LintACL(*acl)
+		remove := make(map[int]bool)
+		for _, f := range findings {
+			rule := acl.Rules[f.Rule]
+			switch f.Kind {
+			case "shadowed":
+				messages = append(messages, fmt.Sprintf("ACL %s: %s (%s) is shadowed by %s and never matches", acl.Ref(), aclRuleLabel(*acl, f.Rule), rule.Action, aclRuleLabel(*acl, f.Other)))
+			case "redundant":
+				messages = append(messages, fmt.Sprintf("ACL %s: %s (%s) is redundant with %s", acl.Ref(), aclRuleLabel(*acl, f.Rule), rule.Action, aclRuleLabel(*acl, f.Other)))
+			case "conflict":
+				messages = append(messages, fmt.Sprintf("ACL %s: %s (%s) overlaps %s with the opposite action", acl.Ref(), aclRuleLabel(*acl, f.Rule), rule.Action, aclRuleLabel(*acl, f.Other)))
+				continue
+			}
+			if prune {
+				remove[f.Rule] = true
+				for j := f.Rule - 1; j >= 0 && acl.Rules[j].Action == "remark"; j-- {
+					remove[j] = true
+				}
+			}
+		}
+		if len(remove) == 0 {
+			continue
+		}
+		var kept []model.ACLRule
+		for j, rule := range acl.Rules {
+			if !remove[j] {
+				kept = append(kept, rule)
+			}
+		}
+		messages = append(messages, fmt.Sprintf("ACL %s: %d entries removed", acl.Ref(), len(acl.Rules)-len(kept)))
+		acl.Rules = kept
+	}
+	return messages
+}
+
+// aclRuleLabel names a rule by its sequence number, or by its position when it has none.
+func aclRuleLabel(acl model.ACL, idx int) string {
+	if seq := acl.Rules[idx].Sequence; seq != 0 {
+		return fmt.Sprintf("rule %d", seq)
+	}
+	pos := 0
+	for j := 0; j <= idx; j++ {
+		if acl.Rules[j].Action != "remark" {
+			pos++
+		}
+	}
+	return fmt.Sprintf("entry #%d", pos)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"converter/model"
+)
+
+// splitNeqPorts rewrites "neq N" port matches, which Huawei ACL rules do not
+// support, as a pair of rules matching "lt N" and "gt N"; a rule with "neq" on
+// both ports becomes four. Sequence numbers of the affected ACLs are cleared,
+// as after object-group expansion. It returns the number of rules split.
+func splitNeqPorts(cfg *model.Config) int {
+	split := 0
+	for i := range cfg.ACLs {
+		acl := &cfg.ACLs[i]
+		var rules []model.ACLRule
+		changed := false
+		for _, rule := range acl.Rules {
+			srcPorts := neqAlternatives(rule.Protocol, rule.SrcPort)
+			dstPorts := neqAlternatives(rule.Protocol, rule.DstPort)
+			if srcPorts[0] == rule.SrcPort && dstPorts[0] == rule.DstPort {
+				rules = append(rules, rule)
+				continue
+			}
+			for _, src := range srcPorts {
+				for _, dst := range dstPorts {
+					r := rule
+					r.SrcPort, r.DstPort = src, dst
+					rules = append(rules, r)
+				}
+			}
+			split++
+			changed = true
+		}
+		if changed {
+			for j := range rules {
+				rules[j].Sequence = 0
+			}
+			acl.Rules = rules
+		}
+	}
+	return split
+}
+
+// neqAlternatives returns the port specs matching the same ports as spec
+// without "neq". Ports 0 and 65535 need only one of "lt" and "gt".
+func neqAlternatives(proto, spec string) []string {
+	parts := strings.Fields(spec)
+	if len(parts) != 2 || parts[0] != "neq" {
+		return []string{spec}
+	}
+	port, ok := model.PortNumber("", proto, parts[1])
+	if !ok {
+		return []string{spec}
+	}
+	var specs []string
+	if port > 0 {
+		specs = append(specs, "lt "+strconv.Itoa(port))
+	}
+	if port < 65535 {
+		specs = append(specs, "gt "+strconv.Itoa(port))
+	}
+	return specs
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"converter/model"
+)
+
+func TestSplitNeqPorts(t *testing.T) {
+	cfg := &model.Config{ACLs: []model.ACL{{Name: "A", Type: "extended", Rules: []model.ACLRule{
+		{Sequence: 10, Action: "permit", Protocol: "tcp", Source: "any", SrcPort: "neq 1024", Destination: "any", DstPort: "neq 80"},
+		{Sequence: 20, Action: "deny", Protocol: "udp", Source: "any", Destination: "any", DstPort: "neq 0"},
+		{Sequence: 30, Action: "permit", Protocol: "tcp", Source: "any", Destination: "any", DstPort: "eq 22"},
+	}}}}
+	if split := splitNeqPorts(cfg); split != 2 {
+		t.Fatalf("split %d rules, want 2", split)
+	}
+	want := [][2]string{
+		{"lt 1024", "lt 80"},
+		{"lt 1024", "gt 80"},
+		{"gt 1024", "lt 80"},
+		{"gt 1024", "gt 80"},
+		{"", "gt 0"},
+		{"", "eq 22"},
+	}
+	rules := cfg.ACLs[0].Rules
+	if len(rules) != len(want) {
+		t.Fatalf("got %d rules, want %d", len(rules), len(want))
+	}
+	for i, r := range rules {
+		if r.SrcPort != want[i][0] || r.DstPort != want[i][1] || r.Sequence != 0 {
+			t.Errorf("rule %d: got %q %q seq %d, want %q %q seq 0", i, r.SrcPort, r.DstPort, r.Sequence, want[i][0], want[i][1])
+		}
+	}
+}
@@ -0,0 +1,737 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"converter/model"
+)
+
+// configChange is one model-level difference between two configs.
+type configChange struct {
+	// Change is added, removed or changed.
+	Change string `json:"change"`
+	Object string `json:"object"`
+	Key    string `json:"key"`
+	Attr   string `json:"attribute,omitempty"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// compareMode selects how strict compareConfigs is.
+type compareMode int
+
+const (
+	// compareDiff reports every difference the model can express.
+	compareDiff compareMode = iota
+	// compareVerify checks a conversion against the config parsed back from it and
+	// skips attributes the target vendor stores differently.
+	compareVerify
+)
+
+// compareConfigs compares two models. Objects are matched by their key (VLAN
+// ID, interface name, ACL name or number, ...); references to ACLs, NAT pools
+// and IS-IS processes are resolved so that renumbering alone is not a difference.
+func compareConfigs(oldCfg, newCfg *model.Config, mode compareMode) []configChange {
+	var changes []configChange
+	compareKeyed(&changes, "vlan", vlanAttrs(oldCfg), vlanAttrs(newCfg))
+	compareKeyed(&changes, "interface", interfaceAttrs(oldCfg, mode), interfaceAttrs(newCfg, mode))
+	compareSets(&changes, "route", routeKeys(oldCfg, mode), routeKeys(newCfg, mode))
+	compareSets(&changes, "vrf route", lineSet(oldCfg.VRFRoutes), lineSet(newCfg.VRFRoutes))
+	compareKeyed(&changes, "ospf process", ospfProcessAttrs(oldCfg), ospfProcessAttrs(newCfg))
+	compareSets(&changes, "ospf network", ospfNetworkKeys(oldCfg), ospfNetworkKeys(newCfg))
+	compareKeyed(&changes, "rip process", ripProcessAttrs(oldCfg), ripProcessAttrs(newCfg))
+	compareKeyed(&changes, "eigrp process", eigrpProcessAttrs(oldCfg), eigrpProcessAttrs(newCfg))
+	compareKeyed(&changes, "isis process", isisProcessAttrs(oldCfg, mode), isisProcessAttrs(newCfg, mode))
+	compareKeyed(&changes, "prefix-list", prefixListAttrs(oldCfg), prefixListAttrs(newCfg))
+	compareKeyed(&changes, "route-map", routeMapAttrs(oldCfg, mode), routeMapAttrs(newCfg, mode))
+	compareSets(&changes, "nat policy", natPolicyKeys(oldCfg), natPolicyKeys(newCfg))
+	compareSets(&changes, "static nat", staticNATKeys(oldCfg), staticNATKeys(newCfg))
+	compareKeyed(&changes, "service", serviceAttrs(oldCfg), serviceAttrs(newCfg))
+	compareKeyed(&changes, "dhcp pool", dhcpPoolAttrs(oldCfg), dhcpPoolAttrs(newCfg))
+	compareSets(&changes, "dhcp excluded", dhcpExcludedKeys(oldCfg), dhcpExcludedKeys(newCfg))
+	compareKeyed(&changes, "dhcp relay", dhcpRelayAttrs(oldCfg), dhcpRelayAttrs(newCfg))
+	compareKeyed(&changes, "time-range", timeRangeAttrs(oldCfg, mode), timeRangeAttrs(newCfg, mode))
+	compareKeyed(&changes, "line", lineAttrs(oldCfg), lineAttrs(newCfg))
+	compareKeyed(&changes, "object-group", objectGroupAttrs(oldCfg), objectGroupAttrs(newCfg))
+
+	oldACLs, newACLs := aclRules(oldCfg), aclRules(newCfg)
+	for _, key := range sortedKeys(oldACLs, newACLs) {
+		before, inOld := oldACLs[key]
+		after, inNew := newACLs[key]
+		switch {
+		case !inNew:
+			changes = append(changes, configChange{Change: "removed", Object: "acl", Key: key})
+		case !inOld:
+			changes = append(changes, configChange{Change: "added", Object: "acl", Key: key})
+		default:
+			// Cisco has no ACL description and keeps it as a remark, which is not compared.
+			if oldDesc, newDesc := aclDescription(oldCfg, key), aclDescription(newCfg, key); mode != compareVerify && oldDesc != newDesc {
+				changes = append(changes, configChange{Change: "changed", Object: "acl", Key: key, Attr: "description", Old: oldDesc, New: newDesc})
+			}
+			changes = append(changes, compareRules(key, before, after)...)
+		}
+	}
+	return changes
+}
+
+// compareRules diffs two rule lists keeping their order: rules outside the
+// longest common subsequence are reported as removed (numbered by their
+// position in the old list) or added (numbered by the position in the new one).
+func compareRules(key string, before, after []string) []configChange {
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var changes []configChange
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			i++
+			j++
+		case i < len(before) && (j == len(after) || lcs[i+1][j] >= lcs[i][j+1]):
+			changes = append(changes, configChange{Change: "removed", Object: "acl", Key: key, Attr: fmt.Sprintf("entry #%d", i+1), Old: before[i]})
+			i++
+		default:
+			changes = append(changes, configChange{Change: "added", Object: "acl", Key: key, Attr: fmt.Sprintf("entry #%d", j+1), New: after[j]})
+			j++
+		}
+	}
+	return changes
+}
+
+// compareKeyed reports objects present on one side only and attributes that differ.
+func compareKeyed(changes *[]configChange, object string, oldItems, newItems map[string]map[string]string) {
+	for _, key := range sortedKeys(oldItems, newItems) {
+		before, inOld := oldItems[key]
+		after, inNew := newItems[key]
+		switch {
+		case !inNew:
+			*changes = append(*changes, configChange{Change: "removed", Object: object, Key: key})
+		case !inOld:
+			*changes = append(*changes, configChange{Change: "added", Object: object, Key: key})
+		default:
+			for _, attr := range sortedKeys(before, after) {
+				if before[attr] != after[attr] {
+					*changes = append(*changes, configChange{Change: "changed", Object: object, Key: key, Attr: attr, Old: before[attr], New: after[attr]})
+				}
+			}
+		}
+	}
+}
+
+func compareSets(changes *[]configChange, object string, oldItems, newItems map[string]bool) {
+	for _, key := range sortedKeys(oldItems, newItems) {
+		switch {
+		case !newItems[key]:
+			*changes = append(*changes, configChange{Change: "removed", Object: object, Key: key})
+		case !oldItems[key]:
+			*changes = append(*changes, configChange{Change: "added", Object: object, Key: key})
+		}
+	}
+}
+
+func sortedKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range []map[string]V{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func serviceAttrs(cfg *model.Config) map[string]map[string]string {
+	return map[string]map[string]string{"global": {
+		"smtp":     strconv.FormatBool(cfg.Service.SMTP),
+		"ftp":      strconv.FormatBool(cfg.Service.FTP),
+		"stp mode": cfg.STP.Mode,
+	}}
+}
+
+func dhcpPoolAttrs(cfg *model.Config) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, p := range cfg.DHCP.Pools {
+		attrs := map[string]string{
+			"network":         strings.TrimSpace(p.Network + " " + p.Mask),
+			"default routers": strings.Join(p.DefaultRouters, " "),
+			"dns servers":     strings.Join(p.DNSServers, " "),
+			"domain name":     p.DomainName,
+			"interface":       p.Interface,
+		}
+		if p.Lease != nil {
+			attrs["lease"] = fmt.Sprintf("%dd %dh %dm infinite=%t", p.Lease.Days, p.Lease.Hours, p.Lease.Minutes, p.Lease.Infinite)
+		}
+		result[p.Name] = attrs
+	}
+	return result
+}
+
+func dhcpExcludedKeys(cfg *model.Config) map[string]bool {
+	result := make(map[string]bool)
+	for _, ex := range cfg.DHCP.Excluded {
+		result[strings.TrimSpace(ex.Start+" "+ex.End)] = true
+	}
+	return result
+}
+
+func dhcpRelayAttrs(cfg *model.Config) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, r := range cfg.DHCP.Relays {
+		servers := append([]string(nil), r.Servers...)
+		sort.Strings(servers)
+		result[r.Interface] = map[string]string{"servers": strings.Join(servers, " ")}
+	}
+	return result
+}
+
+// timeRangeAttrs renders periodic and absolute entries; entries kept raw have no
+// equivalent on the other vendor and are skipped when verifying.
+func timeRangeAttrs(cfg *model.Config, mode compareMode) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, tr := range cfg.TimeRanges {
+		attrs := make(map[string]string)
+		for i, p := range tr.Periodic {
+			attrs[fmt.Sprintf("periodic #%d", i+1)] = fmt.Sprintf("%s %s-%s", strings.Join(p.Days, " "), p.Start, p.End)
+		}
+		if a := tr.Absolute; a != nil {
+			attrs["absolute"] = fmt.Sprintf("%s %s - %s %s", a.StartDate, a.StartTime, a.EndDate, a.EndTime)
+		}
+		if mode != compareVerify {
+			for i, raw := range tr.Raw {
+				attrs[fmt.Sprintf("raw #%d", i+1)] = raw
+			}
+		}
+		result[tr.Name] = attrs
+	}
+	return result
+}
+
+func lineAttrs(cfg *model.Config) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, l := range cfg.Lines {
+		key := fmt.Sprintf("%s %d", l.Type, l.First)
+		if l.Last != 0 {
+			key += fmt.Sprintf(" %d", l.Last)
+		}
+		result[key] = map[string]string{"acl in": aclKey(cfg, l.ACLIn), "acl out": aclKey(cfg, l.ACLOut)}
+	}
+	return result
+}
+
+// prefixListAttrs keys entries by sequence; entries without one get the
+// default step of 5 both vendors use.
+func prefixListAttrs(cfg *model.Config) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, pl := range cfg.PrefixLists {
+		attrs := make(map[string]string)
+		for i, e := range pl.Entries {
+			seq := e.Sequence
+			if seq == 0 {
+				seq = (i + 1) * 5
+			}
+			entry := fmt.Sprintf("%s %s/%d", e.Action, e.Prefix, e.Length)
+			if e.GE != 0 {
+				entry += fmt.Sprintf(" ge %d", e.GE)
+			}
+			if e.LE != 0 {
+				entry += fmt.Sprintf(" le %d", e.LE)
+			}
+			attrs[fmt.Sprintf("seq %d", seq)] = entry
+		}
+		result[pl.Name] = attrs
+	}
+	return result
+}
+
+// routeMapAttrs renders every sequence of a route-map as its action and
+// clauses; ACL references are resolved so that renumbered ACLs compare equal.
+// Raw clauses are skipped when verifying, as for time-ranges.
+func routeMapAttrs(cfg *model.Config, mode compareMode) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, rm := range cfg.RouteMaps {
+		attrs := make(map[string]string)
+		for _, e := range rm.Entries {
+			clauses := []string{e.Action}
+			add := func(clause string, values ...string) {
+				if len(values) > 0 && values[0] != "" {
+					clauses = append(clauses, clause+" "+strings.Join(values, " "))
+				}
+			}
+			var acls []string
+			for _, ref := range e.MatchACLs {
+				acls = append(acls, aclKey(cfg, ref))
+			}
+			add("match prefix-list", e.MatchPrefixLists...)
+			add("match acl", acls...)
+			add("match tag", e.MatchTags...)
+			add("match interface", e.MatchInterfaces...)
+			add("set metric", e.SetMetric)
+			add("set tag", e.SetTag)
+			add("set local-preference", e.SetLocalPreference)
+			add("set next-hop", e.SetNextHop)
+			if mode != compareVerify {
+				for _, raw := range e.Raw {
+					add("raw", raw)
+				}
+			}
+			attrs[fmt.Sprintf("seq %d", e.Sequence)] = strings.Join(clauses, "; ")
+		}
+		result[rm.Name] = attrs
+	}
+	return result
+}
+
+// ospfProcessAttrs covers the process settings; networks are compared separately.
+func ospfProcessAttrs(cfg *model.Config) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, p := range cfg.OSPFProcesses {
+		attrs := make(map[string]string)
+		set := func(attr, value string) {
+			if value != "" && value != "0" && value != "false" {
+				attrs[attr] = value
+			}
+		}
+		set("router id", p.RouterID)
+		set("passive default", strconv.FormatBool(p.PassiveDefault))
+		set("passive interfaces", sortedJoin(p.PassiveInterfaces))
+		set("no passive interfaces", sortedJoin(p.NoPassiveInterfaces))
+		set("reference bandwidth", strconv.Itoa(p.ReferenceBandwidth))
+		if p.DefaultInformation != nil {
+			data, _ := json.Marshal(p.DefaultInformation)
+			set("default information", string(data))
+		}
+		if p.SPFTimers != nil {
+			set("spf timers", fmt.Sprintf("%d %d %d", p.SPFTimers.Start, p.SPFTimers.Hold, p.SPFTimers.Max))
+		}
+		set("redistribute", redistributionList(p.Redistribute))
+		for _, a := range p.Areas {
+			id := normalizeArea(a.ID)
+			a.ID = ""
+			if data, _ := json.Marshal(a); string(data) != `{"id":""}` {
+				set("area "+id, string(data))
+			}
+		}
+		result[strconv.Itoa(p.ProcessID)] = attrs
+	}
+	return result
+}
+
+// ospfAuthentication renders the interface authentication; MD5 keys without a
+// number use key 1, as both generators do.
+func ospfAuthentication(o model.InterfaceOSPF) string {
+	switch o.AuthMode {
+	case "":
+		return ""
+	case "key-chain":
+		return "key-chain " + o.KeyChain
+	}
+	auth := o.AuthMode
+	if o.AuthMode == "md5" {
+		id := o.AuthKeyID
+		if id == 0 {
+			id = 1
+		}
+		auth += " key " + strconv.Itoa(id)
+	}
+	switch {
+	case o.AuthKeyEncrypted:
+		auth += " (encrypted)"
+	case o.AuthKey != "":
+		auth += " " + o.AuthKey
+	}
+	return auth
+}
+
+func redistributionList(list []model.Redistribution) string {
+	var rendered []string
+	for _, r := range list {
+		data, _ := json.Marshal(r)
+		rendered = append(rendered, string(data))
+	}
+	return sortedJoin(rendered)
+}
+
+func ripProcessAttrs(cfg *model.Config) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, p := range cfg.RIP {
+		attrs := make(map[string]string)
+		set := func(attr, value string) {
+			if value != "" && value != "0" && value != "false" {
+				attrs[attr] = value
+			}
+		}
+		set("version", strconv.Itoa(p.Version))
+		set("networks", sortedJoin(p.Networks))
+		set("passive default", strconv.FormatBool(p.PassiveDefault))
+		set("passive interfaces", sortedJoin(p.PassiveInterfaces))
+		set("no passive interfaces", sortedJoin(p.NoPassiveInterfaces))
+		set("no auto-summary", strconv.FormatBool(p.NoAutoSummary))
+		set("redistribute", redistributionList(p.Redistribute))
+		if p.DefaultInformation != nil {
+			data, _ := json.Marshal(p.DefaultInformation)
+			set("default information", string(data))
+		}
+		result[strconv.Itoa(p.ProcessID)] = attrs
+	}
+	return result
+}
+
+func eigrpProcessAttrs(cfg *model.Config) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, p := range cfg.EIGRP {
+		attrs := make(map[string]string)
+		set := func(attr, value string) {
+			if value != "" && value != "0" && value != "false" {
+				attrs[attr] = value
+			}
+		}
+		var networks []string
+		for _, n := range p.Networks {
+			networks = append(networks, strings.TrimSpace(n.Network+" "+n.Wildcard))
+		}
+		set("router id", p.RouterID)
+		set("networks", sortedJoin(networks))
+		set("passive default", strconv.FormatBool(p.PassiveDefault))
+		set("passive interfaces", sortedJoin(p.PassiveInterfaces))
+		set("no passive interfaces", sortedJoin(p.NoPassiveInterfaces))
+		set("no auto-summary", strconv.FormatBool(p.NoAutoSummary))
+		set("redistribute", redistributionList(p.Redistribute))
+		result[strconv.Itoa(p.AS)] = attrs
+	}
+	return result
+}
+
+// isisProcessKey identifies an IS-IS process. Huawei numbers processes instead
+// of naming them, so when verifying a process is matched by its NET addresses,
+// or by its position if it has none.
+func isisProcessKey(cfg *model.Config, tag string, mode compareMode) string {
+	if mode != compareVerify {
+		return tag
+	}
+	for i, p := range cfg.ISIS {
+		if p.Tag != tag {
+			continue
+		}
+		if len(p.NET) > 0 {
+			return "net " + sortedJoin(p.NET)
+		}
+		return fmt.Sprintf("#%d", i+1)
+	}
+	return tag
+}
+
+func isisProcessAttrs(cfg *model.Config, mode compareMode) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, p := range cfg.ISIS {
+		attrs := make(map[string]string)
+		set := func(attr, value string) {
+			if value != "" {
+				attrs[attr] = value
+			}
+		}
+		set("net", sortedJoin(p.NET))
+		set("level", p.Level)
+		set("metric style", p.MetricStyle)
+		set("passive interfaces", sortedJoin(p.PassiveInterfaces))
+		set("redistribute", redistributionList(p.Redistribute))
+		if p.DefaultInformation != nil {
+			data, _ := json.Marshal(p.DefaultInformation)
+			set("default information", string(data))
+		}
+		result[isisProcessKey(cfg, p.Tag, mode)] = attrs
+	}
+	return result
+}
+
+// objectGroupAttrs renders group members in their order; ports are made
+// numeric so that vendor keywords compare equal.
+func objectGroupAttrs(cfg *model.Config) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, g := range cfg.ObjectGroups {
+		attrs := map[string]string{"type": g.Type}
+		if g.Description != "" {
+			attrs["description"] = g.Description
+		}
+		for i, a := range g.Addresses {
+			addr, wildcard := normalizeACLAddress(a.Address, a.Wildcard)
+			if a.End != "" {
+				addr, wildcard = a.Address, "- "+a.End
+			}
+			attrs[fmt.Sprintf("address #%d", i+1)] = strings.TrimSpace(addr + " " + wildcard)
+		}
+		for i, svc := range g.Services {
+			entry := svc.Protocol
+			for _, port := range []string{svc.SrcPort, svc.DstPort} {
+				entry += " " + model.CanonicalPortSpec(cfg.DeviceType, svc.Protocol, port)
+			}
+			attrs[fmt.Sprintf("service #%d", i+1)] = strings.Join(strings.Fields(entry+" "+svc.ICMPType), " ")
+		}
+		if len(g.Groups) > 0 {
+			attrs["groups"] = strings.Join(g.Groups, " ")
+		}
+		result[g.Name] = attrs
+	}
+	return result
+}
+
+// aclDescription returns the description of the ACL with the given key.
+func aclDescription(cfg *model.Config, key string) string {
+	acl, _ := cfg.FindACL(key)
+	return acl.Description
+}
+
+func lineSet(lines []string) map[string]bool {
+	result := make(map[string]bool)
+	for _, line := range lines {
+		result[line] = true
+	}
+	return result
+}
+
+func sortedJoin(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
+
+func vlanAttrs(cfg *model.Config) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, v := range cfg.Vlans {
+		result[strconv.Itoa(v.ID)] = map[string]string{"name": v.Name}
+	}
+	return result
+}
+
+// interfaceAttrs merges repeated sections of the same interface, as the device does.
+// When verifying, the NAT role is left out: Huawei has no inside/outside marking,
+// the parser derives it from "nat outbound", which NAT policies already cover.
+func interfaceAttrs(cfg *model.Config, mode compareMode) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, i := range cfg.Interfaces {
+		attrs, ok := result[i.Name]
+		if !ok {
+			attrs = make(map[string]string)
+			result[i.Name] = attrs
+		}
+		set := func(attr, value string) {
+			if value != "" && value != "0" {
+				attrs[attr] = value
+			}
+		}
+		set("description", i.Description)
+		set("vlan", strconv.Itoa(i.Vlan))
+		set("ip", i.IP)
+		set("trunk vlans", normalizeVlanList(i.TrunkVlans))
+		set("bandwidth", strconv.Itoa(i.Bandwidth))
+		set("acl in", aclKey(cfg, i.ACLIn))
+		set("acl out", aclKey(cfg, i.ACLOut))
+		if o := i.OSPF; o != nil {
+			set("ospf cost", strconv.Itoa(o.Cost))
+			set("ospf area", normalizeArea(o.Area))
+			set("ospf network type", o.NetworkType)
+			if o.Priority != nil {
+				attrs["ospf priority"] = strconv.Itoa(*o.Priority)
+			}
+			set("ospf hello interval", strconv.Itoa(o.HelloInterval))
+			set("ospf dead interval", strconv.Itoa(o.DeadInterval))
+			set("ospf authentication", ospfAuthentication(*o))
+		}
+		if isis := i.ISIS; isis != nil {
+			set("isis process", isisProcessKey(cfg, isis.Tag, mode))
+			set("isis circuit type", isis.CircuitType)
+			set("isis metric", strconv.Itoa(isis.Metric))
+			if isis.PointToPoint {
+				attrs["isis point-to-point"] = "true"
+			}
+		}
+		if mode != compareVerify {
+			set("nat", i.NAT)
+		}
+	}
+	return result
+}
+
+// normalizeVlanList turns "10,20-22" and "10 20 to 22" into "10 20 21 22".
+func normalizeVlanList(raw string) string {
+	tokens := strings.Fields(strings.NewReplacer(",", " ", "-", " to ").Replace(raw))
+	var ids []int
+	for i := 0; i < len(tokens); i++ {
+		start, err := strconv.Atoi(tokens[i])
+		if err != nil {
+			return raw
+		}
+		end := start
+		if i+2 < len(tokens) && tokens[i+1] == "to" {
+			if end, err = strconv.Atoi(tokens[i+2]); err != nil {
+				return raw
+			}
+			i += 2
+		}
+		for id := start; id <= end; id++ {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, " ")
+}
+
+// normalizeArea writes dotted area IDs ("0.0.0.1") as numbers ("1").
+func normalizeArea(area string) string {
+	if v, ok := model.ParseIPv4(area); ok {
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return area
+}
+
+// aclKey resolves an ACL reference to the ACL name, or its number for numbered ACLs.
+func aclKey(cfg *model.Config, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	if acl, ok := cfg.FindACL(ref); ok {
+		return acl.Ref()
+	}
+	return ref
+}
+
+// routeKeys renders every static route with all its attributes. When verifying,
+// the name is compared with spaces replaced by "_", as Cisco names cannot
+// contain them.
+func routeKeys(cfg *model.Config, mode compareMode) map[string]bool {
+	result := make(map[string]bool)
+	for _, r := range cfg.Routes {
+		key := r.Destination + " " + r.Mask
+		if r.Gateway != "" {
+			key += " via " + r.Gateway
+		}
+		if r.Interface != "" {
+			key += " dev " + r.Interface
+		}
+		if r.Distance != 0 {
+			key += " distance " + strconv.Itoa(r.Distance)
+		}
+		if r.Tag != 0 {
+			key += " tag " + strconv.Itoa(r.Tag)
+		}
+		if r.Permanent {
+			key += " permanent"
+		}
+		if r.Track != "" {
+			key += " track " + r.Track
+		}
+		if name := r.Name; name != "" {
+			if mode == compareVerify {
+				name = strings.Join(strings.Fields(name), "_")
+			}
+			key += fmt.Sprintf(" name %q", name)
+		}
+		result[key] = true
+	}
+	return result
+}
+
+func ospfNetworkKeys(cfg *model.Config) map[string]bool {
+	result := make(map[string]bool)
+	for _, p := range cfg.OSPFProcesses {
+		for _, n := range p.Networks {
+			result[fmt.Sprintf("process %d %s %s area %s", p.ProcessID, n.Network, n.Wildcard, normalizeArea(n.Area))] = true
+		}
+	}
+	return result
+}
+
+func natPolicyKeys(cfg *model.Config) map[string]bool {
+	result := make(map[string]bool)
+	for _, p := range cfg.NATRule {
+		key := "acl " + aclKey(cfg, p.ACLRef())
+		if p.Pool != "" {
+			key += " pool " + natPoolRange(cfg, p.Pool)
+		}
+		if p.Outside != "" {
+			key += " outside " + p.Outside
+		}
+		if p.Overload {
+			key += " overload"
+		}
+		result[key] = true
+	}
+	return result
+}
+
+// natPoolRange identifies a pool by its addresses: pool names do not survive a
+// conversion to Huawei address-group numbers.
+func natPoolRange(cfg *model.Config, name string) string {
+	for _, pool := range cfg.NATPool {
+		if pool.Name == name {
+			return pool.Start + "-" + pool.End
+		}
+	}
+	return name
+}
+
+func staticNATKeys(cfg *model.Config) map[string]bool {
+	result := make(map[string]bool)
+	for _, n := range cfg.StaticNAT {
+		key := strings.Join(strings.Fields(strings.Join([]string{n.Protocol, n.InsideAddress, n.InsidePort, n.GlobalAddress, n.GlobalInterface, n.GlobalPort}, " ")), " ")
+		result[key] = true
+	}
+	return result
+}
+
+// aclRules renders the rules of every ACL in a vendor-neutral form. Remarks
+// and sequence numbers are left out: Huawei renumbers rules and merges remarks.
+func aclRules(cfg *model.Config) map[string][]string {
+	result := make(map[string][]string)
+	for _, acl := range cfg.ACLs {
+		var rules []string
+		for _, rule := range acl.Rules {
+			if rule.Action == "remark" {
+				continue
+			}
+			rule.Sequence = 0
+			rule.Protocol = model.CanonicalProtocol(rule.Protocol)
+			if rule.Protocol == "" {
+				rule.Protocol = "ip"
+			}
+			rule.Source, rule.Wildcard = normalizeACLAddress(rule.Source, rule.Wildcard)
+			rule.Destination, rule.DstWildcard = normalizeACLAddress(rule.Destination, rule.DstWildcard)
+			data, _ := json.Marshal(rule)
+			rules = append(rules, string(data))
+		}
+		result[acl.Ref()] = rules
+	}
+	return result
+}
+
+func normalizeACLAddress(addr, wildcard string) (string, string) {
+	if addr == "" || strings.EqualFold(addr, "any") {
+		return "any", ""
+	}
+	if wildcard == "" {
+		wildcard = "0.0.0.0"
+	}
+	return addr, wildcard
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"converter/model"
+	"converter/parser"
+)
+
+// loadConfig reads a config in the given format: cisco, huawei, json or auto.
+func loadConfig(path, format string) (*model.Config, error) {
+	if format == "auto" {
+		var err error
+		if format, err = detectFormat(path); err != nil {
+			return nil, err
+		}
+	}
+	switch format {
+	case "cisco":
+		return parser.ParseCisco(path)
+	case "huawei":
+		return parser.ParseHuawei(path)
+	case "json":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		cfg := &model.Config{}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		model.MigrateLegacy(cfg)
+		return cfg, nil
+	}
+	return nil, fmt.Errorf("unsupported input format %q", format)
+}
+
+// detectFormat guesses the format of a config: a JSON model starts with "{",
+// Huawei configs use "sysname", "#" separators and "quit"/"return".
+func detectFormat(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSpace(string(data))
+	if strings.HasPrefix(text, "{") {
+		return "json", nil
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "sysname ") || line == "#" || line == "quit" || line == "return" || line == "system-view" {
+			return "huawei", nil
+		}
+	}
+	return "cisco", nil
+}
+
+// runDiff implements "converter diff [-a fmt] [-b fmt] [-format text|json] <old> <new>".
+// The exit code is 0 when the configs are equal, 1 when they differ and 2 on errors.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	formatA := fs.String("a", "auto", "Format of the first config: auto|cisco|huawei|json")
+	formatB := fs.String("b", "auto", "Format of the second config: auto|cisco|huawei|json")
+	output := fs.String("format", "text", "Output format: text|json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 || (*output != "text" && *output != "json") {
+		fmt.Println("Usage: converter diff [-a auto|cisco|huawei|json] [-b ...] [-format text|json] <old> <new>")
+		return 2
+	}
+	oldCfg, err := loadConfig(fs.Arg(0), *formatA)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return 2
+	}
+	newCfg, err := loadConfig(fs.Arg(1), *formatB)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return 2
+	}
+
+	changes := compareConfigs(oldCfg, newCfg, compareDiff)
+	if *output == "json" {
+		if changes == nil {
+			changes = []configChange{}
+		}
+		data, _ := json.MarshalIndent(changes, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		for _, c := range changes {
+			fmt.Println(formatChange(c))
+		}
+	}
+	if len(changes) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// formatChange renders a change as "+ added", "- removed" or "~ changed" line.
+func formatChange(c configChange) string {
+	subject := c.Object + " " + c.Key
+	if c.Attr != "" {
+		subject += " " + c.Attr
+	}
+	switch c.Change {
+	case "added":
+		if c.New != "" {
+			return fmt.Sprintf("+ %s: %s", subject, c.New)
+		}
+		return "+ " + subject
+	case "removed":
+		if c.Old != "" {
+			return fmt.Sprintf("- %s: %s", subject, c.Old)
+		}
+		return "- " + subject
+	}
+	return fmt.Sprintf("~ %s: %q -> %q", subject, c.Old, c.New)
+}
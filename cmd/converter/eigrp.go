@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"converter/model"
+)
+
+// ospfDefaultReferenceKbps is the OSPF reference bandwidth used by both vendors (100 Mbit/s).
+const ospfDefaultReferenceKbps = 100000
+
+// translateEIGRPToOSPF replaces every EIGRP process with an OSPF process in
+// area 0. Classful network statements are narrowed to the connected subnets
+// they cover, and interface costs are derived from the configured bandwidth
+// so that path preference keeps following the EIGRP bandwidth metric.
+// It returns human-readable notes describing what was changed.
+func translateEIGRPToOSPF(cfg *model.Config) []string {
+	var notes []string
+	processes := make(map[int]int)
+	for _, e := range cfg.EIGRP {
+		pid := freeOSPFProcessID(cfg, e.AS)
+		p := model.OSPFProcess{
+			ProcessID:           pid,
+			RouterID:            e.RouterID,
+			PassiveDefault:      e.PassiveDefault,
+			PassiveInterfaces:   e.PassiveInterfaces,
+			NoPassiveInterfaces: e.NoPassiveInterfaces,
+		}
+
+		var covered []int
+		for _, n := range e.Networks {
+			if n.Wildcard != "" {
+				p.Networks = appendOSPFNetwork(p.Networks, model.OSPFNetwork{Network: n.Network, Wildcard: n.Wildcard, Area: "0"})
+				covered = append(covered, interfacesInNetwork(cfg, n.Network, model.WildcardToMask(n.Wildcard))...)
+				continue
+			}
+			mask := classfulMask(n.Network)
+			ifaces := interfacesInNetwork(cfg, n.Network, mask)
+			if len(ifaces) == 0 {
+				p.Networks = appendOSPFNetwork(p.Networks, model.OSPFNetwork{Network: n.Network, Wildcard: model.WildcardToMask(mask), Area: "0"})
+				notes = append(notes, fmt.Sprintf("EIGRP AS %d: no interface in classful network %s, advertised as %s %s", e.AS, n.Network, n.Network, model.WildcardToMask(mask)))
+				continue
+			}
+			for _, idx := range ifaces {
+				addr, ifMask := model.SplitIPMask(cfg.Interfaces[idx].IP)
+				p.Networks = appendOSPFNetwork(p.Networks, model.OSPFNetwork{
+					Network:  model.SubnetOf(addr, ifMask),
+					Wildcard: model.WildcardToMask(ifMask),
+					Area:     "0",
+				})
+			}
+			covered = append(covered, ifaces...)
+		}
+
+		for _, r := range e.Redistribute {
+			if r.Protocol == "ospf" && r.Process == strconv.Itoa(pid) {
+				continue
+			}
+			if r.EIGRPMetric != "" {
+				notes = append(notes, fmt.Sprintf("EIGRP AS %d: composite metric %q of redistributed %s dropped, OSPF default seed metric applies", e.AS, r.EIGRPMetric, r.Protocol))
+				r.EIGRPMetric = ""
+			}
+			p.Redistribute = append(p.Redistribute, r)
+		}
+
+		for _, idx := range covered {
+			iface := &cfg.Interfaces[idx]
+			if iface.Bandwidth == 0 || (iface.OSPF != nil && iface.OSPF.Cost != 0) {
+				continue
+			}
+			if iface.OSPF == nil {
+				iface.OSPF = &model.InterfaceOSPF{}
+			}
+			iface.OSPF.Cost = min(max(1, ospfDefaultReferenceKbps/iface.Bandwidth), 65535)
+			notes = append(notes, fmt.Sprintf("EIGRP AS %d: %s OSPF cost %d from bandwidth %d kbit/s", e.AS, iface.Name, iface.OSPF.Cost, iface.Bandwidth))
+		}
+
+		cfg.OSPFProcesses = append(cfg.OSPFProcesses, p)
+		processes[e.AS] = pid
+		notes = append(notes, fmt.Sprintf("EIGRP AS %d translated to OSPF process %d area 0 (%d networks)", e.AS, pid, len(p.Networks)))
+	}
+	// once every process exists, so that a later AS redistributing an earlier one is renamed too
+	for as, pid := range processes {
+		renameEIGRPRedistribution(cfg, as, pid)
+	}
+	for _, r := range cfg.Routes {
+		switch {
+		case len(cfg.EIGRP) == 0:
+		// EIGRP internal routes (AD 90) preferred over these statics, OSPF routes (AD 110) do not.
+		case r.Distance > 90 && r.Distance <= 110:
+			notes = append(notes, fmt.Sprintf("static route %s %s with distance %d now takes precedence over dynamic routes", r.Destination, r.Mask, r.Distance))
+		// these statics were preferred over EIGRP external routes (AD 170), OSPF external routes (AD 110) now win.
+		case r.Distance > 110 && r.Distance < 170:
+			notes = append(notes, fmt.Sprintf("static route %s %s with distance %d now loses to OSPF routes that replace EIGRP external routes", r.Destination, r.Mask, r.Distance))
+		}
+	}
+	cfg.EIGRP = nil
+	return notes
+}
+
+func freeOSPFProcessID(cfg *model.Config, preferred int) int {
+	used := make(map[int]bool)
+	highest := 0
+	for _, p := range cfg.OSPFProcesses {
+		used[p.ProcessID] = true
+		highest = max(highest, p.ProcessID)
+	}
+	if preferred > 0 && preferred <= 65535 && !used[preferred] {
+		return preferred
+	}
+	return highest + 1
+}
+
+// renameEIGRPRedistribution points "redistribute eigrp <as>" of other protocols to the new OSPF process.
+func renameEIGRPRedistribution(cfg *model.Config, as, pid int) {
+	rename := func(list []model.Redistribution) []model.Redistribution {
+		var result []model.Redistribution
+		for _, r := range list {
+			if r.Protocol == "eigrp" && r.Process == strconv.Itoa(as) {
+				r.Protocol = "ospf"
+				r.Process = strconv.Itoa(pid)
+			}
+			result = append(result, r)
+		}
+		return result
+	}
+	for i := range cfg.OSPFProcesses {
+		cfg.OSPFProcesses[i].Redistribute = rename(cfg.OSPFProcesses[i].Redistribute)
+	}
+	for i := range cfg.RIP {
+		cfg.RIP[i].Redistribute = rename(cfg.RIP[i].Redistribute)
+	}
+}
+
+func interfacesInNetwork(cfg *model.Config, network, mask string) []int {
+	var result []int
+	for i, iface := range cfg.Interfaces {
+		addr, ifMask := model.SplitIPMask(iface.IP)
+		if ifMask != "" && model.SubnetContains(network, mask, addr) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func appendOSPFNetwork(list []model.OSPFNetwork, n model.OSPFNetwork) []model.OSPFNetwork {
+	for _, existing := range list {
+		if existing == n {
+			return list
+		}
+	}
+	return append(list, n)
+}
+
+func classfulMask(network string) string {
+	first, _ := strconv.Atoi(strings.SplitN(network, ".", 2)[0])
+	switch {
+	case first < 128:
+		return "255.0.0.0"
+	case first < 192:
+		return "255.255.0.0"
+	default:
+		return "255.255.255.0"
+	}
+}
@@ -66,6 +66,71 @@ func applyInterfaceTransformations(cfg *model.Config, mappings []interfaceMappin
 	for i := range cfg.OSPFNoPassiveIfaces {
 		cfg.OSPFNoPassiveIfaces[i] = mapInterfaceName(cfg.OSPFNoPassiveIfaces[i], mappings, opts)
 	}
+	for i := range cfg.OSPFProcesses {
+		p := &cfg.OSPFProcesses[i]
+		for j := range p.PassiveInterfaces {
+			p.PassiveInterfaces[j] = mapInterfaceName(p.PassiveInterfaces[j], mappings, opts)
+		}
+		for j := range p.NoPassiveInterfaces {
+			p.NoPassiveInterfaces[j] = mapInterfaceName(p.NoPassiveInterfaces[j], mappings, opts)
+		}
+	}
+	for i := range cfg.RIP {
+		p := &cfg.RIP[i]
+		for j := range p.PassiveInterfaces {
+			p.PassiveInterfaces[j] = mapInterfaceName(p.PassiveInterfaces[j], mappings, opts)
+		}
+		for j := range p.NoPassiveInterfaces {
+			p.NoPassiveInterfaces[j] = mapInterfaceName(p.NoPassiveInterfaces[j], mappings, opts)
+		}
+	}
+	for i := range cfg.EIGRP {
+		p := &cfg.EIGRP[i]
+		for j := range p.PassiveInterfaces {
+			p.PassiveInterfaces[j] = mapInterfaceName(p.PassiveInterfaces[j], mappings, opts)
+		}
+		for j := range p.NoPassiveInterfaces {
+			p.NoPassiveInterfaces[j] = mapInterfaceName(p.NoPassiveInterfaces[j], mappings, opts)
+		}
+	}
+	for i := range cfg.ISIS {
+		p := &cfg.ISIS[i]
+		for j := range p.PassiveInterfaces {
+			p.PassiveInterfaces[j] = mapInterfaceName(p.PassiveInterfaces[j], mappings, opts)
+		}
+	}
+	for i := range cfg.StaticNAT {
+		if cfg.StaticNAT[i].Interface != "" {
+			cfg.StaticNAT[i].Interface = mapInterfaceName(cfg.StaticNAT[i].Interface, mappings, opts)
+		}
+		if cfg.StaticNAT[i].GlobalInterface != "" {
+			cfg.StaticNAT[i].GlobalInterface = mapInterfaceName(cfg.StaticNAT[i].GlobalInterface, mappings, opts)
+		}
+	}
+	for i := range cfg.Routes {
+		if cfg.Routes[i].Interface != "" {
+			cfg.Routes[i].Interface = mapInterfaceName(cfg.Routes[i].Interface, mappings, opts)
+		}
+	}
+	for i := range cfg.RouteMaps {
+		for j := range cfg.RouteMaps[i].Entries {
+			entry := &cfg.RouteMaps[i].Entries[j]
+			for k := range entry.MatchInterfaces {
+				entry.MatchInterfaces[k] = mapInterfaceName(entry.MatchInterfaces[k], mappings, opts)
+			}
+		}
+	}
+	for i := range cfg.DHCP.Pools {
+		if cfg.DHCP.Pools[i].Interface != "" {
+			cfg.DHCP.Pools[i].Interface = mapInterfaceName(cfg.DHCP.Pools[i].Interface, mappings, opts)
+		}
+	}
+	for i := range cfg.DHCP.Relays {
+		cfg.DHCP.Relays[i].Interface = mapInterfaceName(cfg.DHCP.Relays[i].Interface, mappings, opts)
+	}
+	for i := range cfg.DHCP.GlobalInterfaces {
+		cfg.DHCP.GlobalInterfaces[i] = mapInterfaceName(cfg.DHCP.GlobalInterfaces[i], mappings, opts)
+	}
 }
 
 func mapInterfaceName(name string, mappings []interfaceMapping, opts interfaceTransformOptions) string {
@@ -8,10 +8,12 @@ import (
 
 	"converter/generator"
 	"converter/model"
-	"converter/parser"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(runDiff(os.Args[2:]))
+	}
 	fmt.Println("Program started")
 	input := flag.String("in", "", "Input config file")
 	output := flag.String("out", "", "Output config file")
@@ -20,34 +22,59 @@ func main() {
 	ifMap := flag.String("if-map", "", "Interface type mapping list, e.g. FastEthernet=GigabitEthernet,GigabitEthernet=10GE")
 	ifIndex := flag.String("if-index", "keep", "Interface index format: keep|2|3")
 	ifIndexPrefix := flag.String("if-index-prefix", "1", "Leading segment for 3-part indexes (e.g. 1 -> 1/0/1)")
+	eigrpMode := flag.String("eigrp", "keep", "EIGRP handling: keep|ospf (translate EIGRP processes to OSPF)")
+	aclLint := flag.String("acl-lint", "off", "ACL analysis: off|report|prune (prune drops shadowed and redundant rules)")
+	aclMap := flag.String("acl-map", "", "ACL number mapping file with \"<old> <new>\" lines")
+	traceACL := flag.String("trace-acl", "", "Evaluate flows against this ACL (number or name) instead of converting")
+	flowText := flag.String("flow", "", "Flow for -trace-acl, e.g. \"tcp 10.0.0.1 192.0.2.10:443\"")
+	flowsFile := flag.String("flows", "", "File with one flow per line for -trace-acl")
+	verifyFile := flag.String("verify", "", "Compare a converted config (in the -to format) with the conversion of -in instead of writing -out")
+	runningFile := flag.String("running", "", "Running config (in the -to format); write the commands turning it into the converted config to -out")
+	groupMode := flag.String("object-groups", "auto", "ACL object-group handling: auto|keep|expand (auto expands for Huawei)")
 	flag.Parse()
 
-	if *input == "" || *output == "" {
+	if *input == "" || (*output == "" && *traceACL == "" && *verifyFile == "") {
 		fmt.Println("Usage: converter -in <file> -out <file> -from cisco -to huawei")
+		fmt.Println("       converter -in <file> -from cisco -to huawei -verify <converted file>")
+		fmt.Println("       converter -in <file> -out <file> -from cisco -to huawei -running <device config>")
+		fmt.Println("       converter diff [-a auto|cisco|huawei|json] [-b ...] [-format text|json] <old> <new>")
+		fmt.Println("       converter -in <file> -from cisco -trace-acl <acl> -flow \"<proto> <src>[:port] <dst>[:port]\"")
 		os.Exit(1)
 	}
 
-	var cfg *model.Config
-	var err error
-
-	switch *from {
-	case "cisco":
-		cfg, err = parser.ParseCisco(*input)
-	case "huawei":
-		cfg, err = parser.ParseHuawei(*input)
-	case "json":
-		cfg = &model.Config{}
-		data, _ := os.ReadFile(*input)
-		json.Unmarshal(data, cfg)
-	default:
+	if *from != "cisco" && *from != "huawei" && *from != "json" {
 		fmt.Println("Unsupported input format")
 		os.Exit(1)
 	}
-
+	cfg, err := loadConfig(*input, *from)
 	if err != nil {
 		fmt.Println("Error:", err)
 		os.Exit(1)
 	}
+	if *traceACL != "" {
+		var flows []string
+		if *flowText != "" {
+			flows = append(flows, *flowText)
+		}
+		if *flowsFile != "" {
+			fromFile, err := loadFlows(*flowsFile)
+			if err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			flows = append(flows, fromFile...)
+		}
+		lines, err := traceFlows(cfg, *traceACL, flows)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		return
+	}
+
 	var mappings []interfaceMapping
 	if *ifMap != "" {
 		mappings, err = parseInterfaceMappings(*ifMap)
@@ -68,6 +95,143 @@ func main() {
 		})
 	}
 
+	if (*to == "cisco" || *to == "huawei") && cfg.DeviceType != *to {
+		for _, line := range cfg.VRFRoutes {
+			fmt.Printf("Warning: VRFs are not converted, static route left as a comment: %s\n", line)
+		}
+	}
+
+	switch *eigrpMode {
+	case "ospf":
+		for _, note := range translateEIGRPToOSPF(cfg) {
+			fmt.Println("Note:", note)
+		}
+	case "keep":
+		if *to == "huawei" {
+			for _, p := range cfg.EIGRP {
+				fmt.Printf("Warning: EIGRP AS %d is not supported on Huawei and was not converted; use -eigrp ospf to translate it\n", p.AS)
+			}
+		}
+	default:
+		fmt.Println("Error: -eigrp must be one of keep|ospf")
+		os.Exit(1)
+	}
+
+	switch *groupMode {
+	case "auto", "keep", "expand":
+	default:
+		fmt.Println("Error: -object-groups must be one of auto|keep|expand")
+		os.Exit(1)
+	}
+	// Huawei ACL rules cannot reference service-sets, so groups are expanded by default.
+	if *groupMode == "expand" || (*groupMode == "auto" && *to == "huawei") {
+		expanded, produced, err := expandObjectGroups(cfg)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		if expanded > 0 {
+			fmt.Printf("Note: object-groups: %d rules expanded into %d\n", expanded, produced)
+		}
+	} else if *to == "huawei" {
+		if err := checkServiceGroupsKept(cfg); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+	if *to == "huawei" {
+		if split := splitNeqPorts(cfg); split > 0 {
+			fmt.Printf("Note: Huawei ACLs have no \"neq\" port match, %d rules split into \"lt\" and \"gt\" rules\n", split)
+		}
+	}
+
+	switch *aclLint {
+	case "off":
+	case "report", "prune":
+		for _, msg := range lintACLs(cfg, *aclLint == "prune") {
+			fmt.Println("ACL lint:", msg)
+		}
+	default:
+		fmt.Println("Error: -acl-lint must be one of off|report|prune")
+		os.Exit(1)
+	}
+
+	var running *model.Config
+	if *runningFile != "" {
+		if *to != "cisco" && *to != "huawei" {
+			fmt.Println("Error: -running requires -to cisco or -to huawei")
+			os.Exit(1)
+		}
+		running, err = loadConfig(*runningFile, *to)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	if *to == "huawei" || *to == "cisco" {
+		explicit := map[string]int{}
+		if *aclMap != "" {
+			explicit, err = loadACLMapping(*aclMap)
+			if err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+		}
+		table, err := allocateACLIDs(cfg, *to, explicit, running)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		if len(table) > 0 {
+			fmt.Println("ACL numbers:")
+		}
+		for _, row := range table {
+			line := fmt.Sprintf("  %s -> %d", row.ref, row.newID)
+			if row.reason != "" {
+				line += " (" + row.reason + ")"
+			}
+			fmt.Println(line)
+		}
+	}
+
+	if *verifyFile != "" {
+		converted, err := loadConfig(*verifyFile, *to)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		diffs := verifyConfigs(cfg, converted)
+		for _, d := range diffs {
+			fmt.Println("Difference:", d)
+		}
+		if len(diffs) > 0 {
+			fmt.Printf("Verification failed: %d differences\n", len(diffs))
+			os.Exit(2)
+		}
+		fmt.Println("Verification passed:", *verifyFile)
+		return
+	}
+
+	if running != nil {
+		generateDelta := generator.GenerateCiscoDelta
+		if *to == "huawei" {
+			generateDelta = generator.GenerateHuaweiDelta
+		}
+		result, skipped := generateDelta(running, cfg)
+		rollback, _ := generateDelta(cfg, running)
+		for _, section := range skipped {
+			fmt.Printf("Warning: %s differ between the running and converted configs and are not included in the delta; apply them manually\n", section)
+		}
+		if err := os.WriteFile(*output, []byte(result), 0644); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		writeRollback(*output, rollback)
+		fmt.Println("Delta written:", *output)
+		return
+	}
+
 	switch *to {
 	case "json":
 		data, err := json.MarshalIndent(cfg, "", "  ")
@@ -85,12 +249,14 @@ func main() {
 			fmt.Println("Error:", err)
 			os.Exit(1)
 		}
+		writeRollback(*output, generator.GenerateHuaweiRollback(cfg))
 	case "cisco":
 		result := generator.GenerateCisco(cfg)
 		if err := os.WriteFile(*output, []byte(result), 0644); err != nil {
 			fmt.Println("Error:", err)
 			os.Exit(1)
 		}
+		writeRollback(*output, generator.GenerateCiscoRollback(cfg))
 	default:
 		fmt.Println("Unsupported output format")
 		os.Exit(1)
@@ -98,3 +264,14 @@ func main() {
 
 	fmt.Println("Conversion complete:", *output)
 }
+
+// writeRollback stores the back-out script for a generated config next to it
+// as "<out>.rollback".
+func writeRollback(output, script string) {
+	path := output + ".rollback"
+	if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	fmt.Println("Rollback written:", path)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+
+	"converter/model"
+)
+
+// checkServiceGroupsKept fails for the first ACL rule that references a
+// service group: Huawei ACL rules cannot reference one, and writing the rule
+// as a comment would silently drop the permit or deny.
+func checkServiceGroupsKept(cfg *model.Config) error {
+	for _, acl := range cfg.ACLs {
+		for idx, rule := range acl.Rules {
+			if rule.ServiceGroup != "" {
+				return fmt.Errorf("ACL %s %s: service group %s cannot be kept for Huawei, use -object-groups expand", acl.Ref(), aclRuleLabel(acl, idx), rule.ServiceGroup)
+			}
+		}
+	}
+	return nil
+}
+
+// expandObjectGroups replaces every object-group reference in ACL rules with
+// plain rules: nested groups are flattened, address ranges are split into
+// wildcard blocks and "tcp-udp" services become a tcp and a udp rule. Each rule
+// is multiplied out over sources × destinations × services. Sequence numbers of
+// the affected ACLs are cleared because one rule may turn into many. It returns
+// the number of rules that referenced groups and the number of rules produced.
+// A reference to an undefined, empty or wrong-type group is an error: dropping
+// the rule would silently change what the ACL permits; the config is then left
+// unchanged.
+func expandObjectGroups(cfg *model.Config) (expanded, produced int, err error) {
+	expandedRules := make(map[int][]model.ACLRule)
+	for i := range cfg.ACLs {
+		acl := &cfg.ACLs[i]
+		var rules []model.ACLRule
+		changed := false
+		for idx, rule := range acl.Rules {
+			if rule.SourceGroup == "" && rule.DestinationGroup == "" && rule.ServiceGroup == "" {
+				rules = append(rules, rule)
+				continue
+			}
+			result, err := expandACLRule(cfg, rule)
+			if err != nil {
+				return 0, 0, fmt.Errorf("ACL %s %s: %w", acl.Ref(), aclRuleLabel(*acl, idx), err)
+			}
+			expanded++
+			produced += len(result)
+			rules = append(rules, result...)
+			changed = true
+		}
+		if changed {
+			for j := range rules {
+				rules[j].Sequence = 0
+			}
+			expandedRules[i] = rules
+		}
+	}
+	for i, rules := range expandedRules {
+		cfg.ACLs[i].Rules = rules
+	}
+	cfg.ObjectGroups = nil
+	return expanded, produced, nil
+}
+
+func expandACLRule(cfg *model.Config, rule model.ACLRule) ([]model.ACLRule, error) {
+	var err error
+	sources := [][2]string{{rule.Source, rule.Wildcard}}
+	if rule.SourceGroup != "" {
+		if sources, err = groupAddressBlocks(cfg, rule.SourceGroup, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+	destinations := [][2]string{{rule.Destination, rule.DstWildcard}}
+	if rule.DestinationGroup != "" {
+		if destinations, err = groupAddressBlocks(cfg, rule.DestinationGroup, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+	services := []model.GroupService{{Protocol: rule.Protocol, SrcPort: rule.SrcPort, DstPort: rule.DstPort, ICMPType: rule.ICMPType}}
+	if rule.ServiceGroup != "" {
+		if services, err = groupServices(cfg, rule.ServiceGroup, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []model.ACLRule
+	for _, svc := range services {
+		for _, src := range sources {
+			for _, dst := range destinations {
+				r := rule
+				r.SourceGroup, r.DestinationGroup, r.ServiceGroup = "", "", ""
+				r.Source, r.Wildcard = src[0], src[1]
+				r.Destination, r.DstWildcard = dst[0], dst[1]
+				if rule.ServiceGroup != "" {
+					r.Protocol, r.SrcPort, r.DstPort, r.ICMPType = svc.Protocol, svc.SrcPort, svc.DstPort, svc.ICMPType
+					r.ICMPCode = ""
+				}
+				result = append(result, r)
+			}
+		}
+	}
+	return result, nil
+}
+
+// findGroup looks up a group of the given type referenced by an ACL rule.
+func findGroup(cfg *model.Config, name, groupType string) (model.ObjectGroup, error) {
+	g, ok := cfg.FindObjectGroup(name)
+	if !ok {
+		return g, fmt.Errorf("object-group %s is not defined", name)
+	}
+	if g.Type != groupType {
+		return g, fmt.Errorf("object-group %s is a %s group, expected %s", name, g.Type, groupType)
+	}
+	return g, nil
+}
+
+// groupAddressBlocks returns the address/wildcard pairs of a network group,
+// including its nested groups. seen guards against reference loops.
+func groupAddressBlocks(cfg *model.Config, name string, seen map[string]bool) ([][2]string, error) {
+	if seen[name] {
+		return nil, nil
+	}
+	g, err := findGroup(cfg, name, "network")
+	if err != nil {
+		return nil, err
+	}
+	seen[name] = true
+	var result [][2]string
+	for _, a := range g.Addresses {
+		if a.End != "" {
+			result = append(result, model.RangeBlocks(a.Address, a.End)...)
+			continue
+		}
+		result = append(result, [2]string{a.Address, a.Wildcard})
+	}
+	for _, nested := range g.Groups {
+		blocks, err := groupAddressBlocks(cfg, nested, seen)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, blocks...)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("object-group %s is empty", name)
+	}
+	return result, nil
+}
+
+func groupServices(cfg *model.Config, name string, seen map[string]bool) ([]model.GroupService, error) {
+	if seen[name] {
+		return nil, nil
+	}
+	g, err := findGroup(cfg, name, "service")
+	if err != nil {
+		return nil, err
+	}
+	seen[name] = true
+	var result []model.GroupService
+	for _, svc := range g.Services {
+		if svc.Protocol == "tcp-udp" {
+			tcp, udp := svc, svc
+			tcp.Protocol, udp.Protocol = "tcp", "udp"
+			result = append(result, tcp, udp)
+			continue
+		}
+		result = append(result, svc)
+	}
+	for _, nested := range g.Groups {
+		services, err := groupServices(cfg, nested, seen)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, services...)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("object-group %s is empty", name)
+	}
+	return result, nil
+}
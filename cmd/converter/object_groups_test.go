@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"converter/model"
+)
+
+func objectGroupTestConfig(rules ...model.ACLRule) *model.Config {
+	return &model.Config{
+		ObjectGroups: []model.ObjectGroup{
+			{Name: "SRV", Type: "network", Addresses: []model.GroupAddress{
+				{Address: "10.0.0.1"},
+				{Address: "10.0.0.4", End: "10.0.0.7"},
+			}},
+			{Name: "WEB", Type: "service", Services: []model.GroupService{
+				{Protocol: "tcp", DstPort: "eq 80"},
+				{Protocol: "tcp-udp", DstPort: "eq 53"},
+			}},
+			{Name: "EMPTY", Type: "network"},
+		},
+		ACLs: []model.ACL{{Name: "A", Type: "extended", Rules: rules}},
+	}
+}
+
+func TestExpandObjectGroups(t *testing.T) {
+	cfg := objectGroupTestConfig(
+		model.ACLRule{Action: "deny", Protocol: "tcp", SourceGroup: "SRV", Destination: "any", DstPort: "eq 22"},
+		model.ACLRule{Action: "permit", ServiceGroup: "WEB", Source: "any", DestinationGroup: "SRV"},
+		model.ACLRule{Action: "permit", Protocol: "ip", Source: "any", Destination: "any"},
+	)
+	expanded, produced, err := expandObjectGroups(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expanded != 2 || produced != 8 {
+		t.Fatalf("expanded %d rules into %d, want 2 into 8", expanded, produced)
+	}
+	rules := cfg.ACLs[0].Rules
+	if len(rules) != 9 {
+		t.Fatalf("got %d rules, want 9", len(rules))
+	}
+	if r := rules[1]; r.Action != "deny" || r.Source != "10.0.0.4" || r.Wildcard != "0.0.0.3" {
+		t.Errorf("range not expanded into a wildcard block: %+v", r)
+	}
+	protocols := map[string]int{}
+	for _, r := range rules[2:8] {
+		protocols[r.Protocol]++
+		if r.ServiceGroup != "" || r.DestinationGroup != "" {
+			t.Errorf("group reference left in rule %+v", r)
+		}
+	}
+	if protocols["tcp"] != 4 || protocols["udp"] != 2 {
+		t.Errorf("tcp-udp not split: %v", protocols)
+	}
+	if cfg.ObjectGroups != nil {
+		t.Errorf("object groups not removed")
+	}
+}
+
+func TestExpandObjectGroupsKeepsRulesOnBadReference(t *testing.T) {
+	tests := []struct {
+		name string
+		rule model.ACLRule
+		want string
+	}{
+		{"undefined", model.ACLRule{Action: "deny", Protocol: "ip", SourceGroup: "BAD", Destination: "any"}, "not defined"},
+		{"wrong type", model.ACLRule{Action: "deny", Protocol: "ip", SourceGroup: "WEB", Destination: "any"}, "expected network"},
+		{"empty", model.ACLRule{Action: "deny", Protocol: "ip", Source: "any", DestinationGroup: "EMPTY"}, "is empty"},
+		{"service as network", model.ACLRule{Action: "deny", ServiceGroup: "SRV", Source: "any", Destination: "any"}, "expected service"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := objectGroupTestConfig(tt.rule, model.ACLRule{Action: "permit", Protocol: "ip", Source: "any", Destination: "any"})
+			_, _, err := expandObjectGroups(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want %q", err, tt.want)
+			}
+			if len(cfg.ACLs[0].Rules) != 2 || len(cfg.ObjectGroups) != 3 {
+				t.Errorf("config modified despite the error")
+			}
+		})
+	}
+}
+
+func TestCheckServiceGroupsKept(t *testing.T) {
+	cfg := objectGroupTestConfig(model.ACLRule{Action: "permit", Protocol: "ip", SourceGroup: "SRV", Destination: "any"})
+	if err := checkServiceGroupsKept(cfg); err != nil {
+		t.Fatalf("network group rejected: %v", err)
+	}
+	cfg.ACLs[0].Rules = append(cfg.ACLs[0].Rules, model.ACLRule{Action: "permit", ServiceGroup: "WEB", Source: "any", Destination: "any"})
+	if err := checkServiceGroupsKept(cfg); err == nil || !strings.Contains(err.Error(), "WEB") {
+		t.Fatalf("got error %v, want one naming WEB", err)
+	}
+}
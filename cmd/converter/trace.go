@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"converter/model"
+)
+
+// parseFlow reads "<proto> <src>[:port] <dst>[:port] [established] [fragment]
+// [icmp-type <type>] [dscp <value>]", e.g. "tcp 10.0.0.1 192.0.2.10:443".
+func parseFlow(text string) (model.Flow, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 3 {
+		return model.Flow{}, fmt.Errorf("invalid flow %q: expected <proto> <src>[:port] <dst>[:port]", text)
+	}
+	flow := model.Flow{Protocol: model.CanonicalProtocol(fields[0])}
+	var err error
+	if flow.Source, flow.SrcPort, err = parseFlowEndpoint(flow.Protocol, fields[1]); err != nil {
+		return model.Flow{}, err
+	}
+	if flow.Destination, flow.DstPort, err = parseFlowEndpoint(flow.Protocol, fields[2]); err != nil {
+		return model.Flow{}, err
+	}
+	for i := 3; i < len(fields); i++ {
+		switch fields[i] {
+		case "established":
+			flow.Established = true
+		case "fragment":
+			flow.Fragment = true
+		case "icmp-type", "dscp":
+			if i+1 >= len(fields) {
+				return model.Flow{}, fmt.Errorf("invalid flow %q: %s needs a value", text, fields[i])
+			}
+			if fields[i] == "icmp-type" {
+				flow.ICMPType = fields[i+1]
+			} else {
+				flow.DSCP = fields[i+1]
+			}
+			i++
+		default:
+			return model.Flow{}, fmt.Errorf("invalid flow %q: unknown option %q", text, fields[i])
+		}
+	}
+	return flow, nil
+}
+
+func parseFlowEndpoint(proto, text string) (string, int, error) {
+	addr, portText, hasPort := strings.Cut(text, ":")
+	if !model.IsIPv4(addr) {
+		return "", 0, fmt.Errorf("invalid flow address %q", addr)
+	}
+	if !hasPort {
+		return addr, 0, nil
+	}
+	port, ok := model.PortNumber("", proto, portText)
+	if !ok || port == 0 {
+		return "", 0, fmt.Errorf("invalid flow port %q", portText)
+	}
+	return addr, port, nil
+}
+
+// loadFlows reads one flow per line; empty lines and "#" comments are skipped.
+func loadFlows(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var result []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			result = append(result, line)
+		}
+	}
+	return result, scanner.Err()
+}
+
+// traceFlows evaluates each flow against the ACL and returns one result line per flow.
+func traceFlows(cfg *model.Config, ref string, flows []string) ([]string, error) {
+	acl, ok := cfg.FindACL(ref)
+	if !ok {
+		return nil, fmt.Errorf("ACL %s not found", ref)
+	}
+	var lines []string
+	for _, text := range flows {
+		flow, err := parseFlow(text)
+		if err != nil {
+			return nil, err
+		}
+		verdict := cfg.EvaluateACL(acl, flow)
+		matched := "implicit deny"
+		if verdict.Index >= 0 {
+			matched = aclRuleLabel(acl, verdict.Index)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s (%s)", formatFlow(flow), verdict.Action, matched))
+	}
+	return lines, nil
+}
+
+func formatFlow(flow model.Flow) string {
+	endpoint := func(addr string, port int) string {
+		if port == 0 {
+			return addr
+		}
+		return addr + ":" + strconv.Itoa(port)
+	}
+	return fmt.Sprintf("%s %s -> %s", flow.Protocol, endpoint(flow.Source, flow.SrcPort), endpoint(flow.Destination, flow.DstPort))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"converter/model"
+)
+
+// verifyConfigs compares the model expected from the conversion with the model
+// parsed back from the converted config and returns one line per difference.
+func verifyConfigs(expected, actual *model.Config) []string {
+	var diffs []string
+	for _, c := range compareConfigs(expected, actual, compareVerify) {
+		subject := c.Object + " " + c.Key
+		if c.Attr != "" {
+			subject += " " + c.Attr
+		}
+		switch {
+		case c.Change == "changed":
+			diffs = append(diffs, fmt.Sprintf("%s: expected %q, got %q", subject, c.Old, c.New))
+		case c.Change == "removed" && c.Old != "":
+			diffs = append(diffs, fmt.Sprintf("%s: missing %s", subject, c.Old))
+		case c.Change == "removed":
+			diffs = append(diffs, fmt.Sprintf("%s: missing in converted config", subject))
+		case c.New != "":
+			diffs = append(diffs, fmt.Sprintf("%s: unexpected %s", subject, c.New))
+		default:
+			diffs = append(diffs, fmt.Sprintf("%s: not in original config", subject))
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"converter/model"
+)
+
+func verifyTestConfig() *model.Config {
+	priority := 1
+	return &model.Config{
+		Interfaces: []model.Interface{{
+			Name: "Gi0/1",
+			IP:   "10.0.0.1 255.255.255.0",
+			OSPF: &model.InterfaceOSPF{NetworkType: "point-to-point", Priority: &priority, HelloInterval: 5, AuthMode: "md5", AuthKey: "secret"},
+		}},
+		Routes:       []model.Route{{Destination: "192.168.0.0", Mask: "255.255.255.0", Gateway: "10.0.0.2", Name: "uplink"}},
+		RIP:          []model.RIPProcess{{ProcessID: 1, Version: 2, Networks: []string{"10.0.0.0"}}},
+		EIGRP:        []model.EIGRPProcess{{AS: 10, Networks: []model.EIGRPNetwork{{Network: "10.0.0.0"}}}},
+		ISIS:         []model.ISISProcess{{Tag: "core", NET: []string{"49.0001.0000.0000.0001.00"}, Level: "level-2"}},
+		ObjectGroups: []model.ObjectGroup{{Name: "SRV", Type: "network", Addresses: []model.GroupAddress{{Address: "10.0.0.1"}}}},
+	}
+}
+
+func TestVerifyConfigsCatchesDifferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *model.Config)
+		want   string
+	}{
+		{"route name", func(cfg *model.Config) { cfg.Routes[0].Name = "backup" }, `name "backup": not in original config`},
+		{"route permanent", func(cfg *model.Config) { cfg.Routes[0].Permanent = true }, "permanent"},
+		{"route track", func(cfg *model.Config) { cfg.Routes[0].Track = "1" }, "track 1"},
+		{"ospf network type", func(cfg *model.Config) { cfg.Interfaces[0].OSPF.NetworkType = "broadcast" }, "ospf network type"},
+		{"ospf priority", func(cfg *model.Config) { cfg.Interfaces[0].OSPF.Priority = nil }, "ospf priority"},
+		{"ospf timers", func(cfg *model.Config) { cfg.Interfaces[0].OSPF.HelloInterval = 10 }, "ospf hello interval"},
+		{"ospf authentication", func(cfg *model.Config) { cfg.Interfaces[0].OSPF.AuthMode = "" }, "ospf authentication"},
+		{"rip", func(cfg *model.Config) { cfg.RIP[0].Networks = nil }, "rip process 1"},
+		{"eigrp", func(cfg *model.Config) { cfg.EIGRP = nil }, "eigrp process 10"},
+		{"isis", func(cfg *model.Config) { cfg.ISIS[0].Level = "level-1-2" }, "isis process"},
+		{"object-group", func(cfg *model.Config) { cfg.ObjectGroups[0].Addresses[0].Address = "10.0.0.2" }, "object-group SRV"},
+	}
+	if diffs := verifyConfigs(verifyTestConfig(), verifyTestConfig()); len(diffs) != 0 {
+		t.Fatalf("identical configs differ: %v", diffs)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := verifyTestConfig()
+			tt.change(actual)
+			diffs := verifyConfigs(verifyTestConfig(), actual)
+			if !strings.Contains(strings.Join(diffs, "\n"), tt.want) {
+				t.Errorf("got %v, want a difference about %q", diffs, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyConfigsMatchesRenamedISISProcess(t *testing.T) {
+	expected := verifyTestConfig()
+	expected.Interfaces[0].ISIS = &model.InterfaceISIS{Tag: "core"}
+	actual := verifyTestConfig()
+	actual.ISIS[0].Tag = "1"
+	actual.Interfaces[0].ISIS = &model.InterfaceISIS{Tag: "1"}
+	if diffs := verifyConfigs(expected, actual); len(diffs) != 0 {
+		t.Errorf("renumbered IS-IS process reported: %v", diffs)
+	}
+	if changes := compareConfigs(expected, actual, compareDiff); len(changes) == 0 {
+		t.Errorf("diff does not report the renamed IS-IS process")
+	}
+}
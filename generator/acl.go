@@ -0,0 +1,511 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"converter/model"
+)
+
+func writeCiscoACLs(sb *strings.Builder, cfg *model.Config) {
+	for _, acl := range cfg.ACLs {
+		if acl.Name == "" {
+			if acl.Description != "" {
+				sb.WriteString(fmt.Sprintf("access-list %d remark %s\n", acl.ID, acl.Description))
+			}
+			for _, rule := range acl.Rules {
+				sb.WriteString(fmt.Sprintf("access-list %d %s\n", acl.ID, formatCiscoACLRule(rule, acl.Type)))
+			}
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("ip access-list %s %s\n", ciscoACLKind(acl), acl.Name))
+		if acl.Description != "" {
+			// у Cisco нет описания списка, оно переносится первым примечанием
+			sb.WriteString(fmt.Sprintf(" remark %s\n", acl.Description))
+		}
+		for _, rule := range acl.Rules {
+			line := " "
+			if rule.Sequence != 0 {
+				line += strconv.Itoa(rule.Sequence) + " "
+			}
+			sb.WriteString(line + formatCiscoACLRule(rule, acl.Type) + "\n")
+		}
+		sb.WriteString(" exit\n")
+	}
+}
+
+// ciscoACLKind выбирает standard или extended для блока "ip access-list".
+func ciscoACLKind(acl model.ACL) string {
+	for _, rule := range acl.Rules {
+		if isExtendedACLRule(rule, acl.Type) {
+			return "extended"
+		}
+	}
+	return "standard"
+}
+
+func formatCiscoGroupOrAddress(group, addr, wildcard string) string {
+	if group != "" {
+		return "object-group " + group
+	}
+	return formatCiscoAddress(addr, wildcard)
+}
+
+// formatCiscoACLRule формирует тело правила без "access-list <id>".
+func formatCiscoACLRule(rule model.ACLRule, aclType string) string {
+	if rule.Raw != "" {
+		return rule.Raw
+	}
+	if rule.Action == "remark" {
+		return "remark " + rule.Remark
+	}
+	action := rule.Action
+	if action == "" {
+		action = "permit"
+	}
+	if !isExtendedACLRule(rule, aclType) {
+		line := fmt.Sprintf("%s %s", action, formatCiscoAddress(rule.Source, rule.Wildcard))
+		if rule.Log {
+			line += " log"
+		}
+		return line
+	}
+	proto := rule.Protocol
+	if proto == "" {
+		proto = "ip"
+	}
+	line := fmt.Sprintf("%s %s", action, model.ProtocolKeyword("cisco", proto))
+	if rule.ServiceGroup != "" {
+		line = fmt.Sprintf("%s object-group %s", action, rule.ServiceGroup)
+	}
+	line += " " + formatCiscoGroupOrAddress(rule.SourceGroup, rule.Source, rule.Wildcard)
+	if rule.SrcPort != "" {
+		line += " " + model.VendorPortSpec("cisco", proto, rule.SrcPort)
+	}
+	line += " " + formatCiscoGroupOrAddress(rule.DestinationGroup, rule.Destination, rule.DstWildcard)
+	if rule.DstPort != "" {
+		line += " " + model.VendorPortSpec("cisco", proto, rule.DstPort)
+	}
+	if rule.ICMPType != "" {
+		line += " " + rule.ICMPType
+		if rule.ICMPCode != "" {
+			line += " " + rule.ICMPCode
+		}
+	}
+	if rule.Established {
+		line += " established"
+	}
+	if rule.Precedence != "" {
+		line += " precedence " + rule.Precedence
+	}
+	if rule.TOS != "" {
+		line += " tos " + rule.TOS
+	}
+	if rule.DSCP != "" {
+		line += " dscp " + rule.DSCP
+	}
+	if rule.Fragments {
+		line += " fragments"
+	}
+	if rule.TimeRange != "" {
+		line += " time-range " + rule.TimeRange
+	}
+	if rule.Log {
+		line += " log"
+	}
+	return line
+}
+
+func writeHuaweiACLs(sb *strings.Builder, cfg *model.Config) {
+	for _, acl := range cfg.ACLs {
+		sb.WriteString(formatHuaweiACLHeader(acl))
+		if acl.Description != "" {
+			sb.WriteString(fmt.Sprintf(" description %s\n", acl.Description))
+		}
+		ids := huaweiRuleIDs(acl)
+		// примечания Cisco стоят перед правилом, у Huawei это "rule N remark" после него
+		var remarks []string
+		for idx, rule := range acl.Rules {
+			if rule.Action == "remark" {
+				remarks = append(remarks, rule.Remark)
+				continue
+			}
+			if rule.Raw != "" || rule.ServiceGroup != "" {
+				for _, remark := range remarks {
+					sb.WriteString(fmt.Sprintf(" # remark: %s\n", remark))
+				}
+				remarks = nil
+			}
+			if rule.Raw != "" {
+				sb.WriteString(fmt.Sprintf(" # unsupported ACL rule: %s\n", rule.Raw))
+				continue
+			}
+			if rule.ServiceGroup != "" {
+				// сервисные группы в правилах ACL Huawei не поддерживаются, их нужно развернуть
+				sb.WriteString(fmt.Sprintf(" # unsupported ACL rule: %s object-group %s\n", rule.Action, rule.ServiceGroup))
+				continue
+			}
+			action := rule.Action
+			if action == "" {
+				action = "permit"
+			}
+			sb.WriteString(fmt.Sprintf(" rule %d %s %s\n", ids[idx], action, formatHuaweiACLRule(rule, acl.Type)))
+			if len(remarks) > 0 {
+				sb.WriteString(fmt.Sprintf(" rule %d remark %s\n", ids[idx], strings.Join(remarks, "; ")))
+				remarks = nil
+			}
+		}
+		for _, remark := range remarks {
+			sb.WriteString(fmt.Sprintf(" # remark: %s\n", remark))
+		}
+		sb.WriteString("quit\n\n")
+	}
+}
+
+// huaweiRuleIDs возвращает номер "rule N" для каждого правила списка; примечания
+// и правила, которые не выводятся, получают 0. Правила без номера нумеруются с шагом 5.
+func huaweiRuleIDs(acl model.ACL) []int {
+	ids := make([]int, len(acl.Rules))
+	seq := 5
+	for i, rule := range acl.Rules {
+		if rule.Action == "remark" || rule.Raw != "" || rule.ServiceGroup != "" {
+			continue
+		}
+		ids[i] = rule.Sequence
+		if ids[i] == 0 {
+			ids[i] = seq
+		}
+		seq += 5
+	}
+	return ids
+}
+
+// ciscoRuleIDs возвращает порядковые номера записей так, как их назначает IOS:
+// записи без номера, включая примечания, получают следующий номер с шагом 10.
+func ciscoRuleIDs(acl model.ACL) []int {
+	ids := make([]int, len(acl.Rules))
+	next := 10
+	for i, rule := range acl.Rules {
+		ids[i] = rule.Sequence
+		if ids[i] == 0 {
+			ids[i] = next
+		}
+		next = ids[i] + 10
+	}
+	return ids
+}
+
+func formatHuaweiGroupOrAddress(group, addr, wildcard string) string {
+	if group != "" {
+		return "address-set " + group
+	}
+	return formatHuaweiAddress(addr, wildcard)
+}
+
+// formatHuaweiACLRule формирует условия правила после "rule <n> <action>".
+func formatHuaweiACLRule(rule model.ACLRule, aclType string) string {
+	var line string
+	if isExtendedACLRule(rule, aclType) {
+		proto := rule.Protocol
+		if proto == "" {
+			proto = "ip"
+		}
+		line = fmt.Sprintf("%s source %s", model.ProtocolKeyword("huawei", proto), formatHuaweiGroupOrAddress(rule.SourceGroup, rule.Source, rule.Wildcard))
+		if rule.SrcPort != "" {
+			line += " source-port " + model.VendorPortSpec("huawei", proto, rule.SrcPort)
+		}
+		line += " destination " + formatHuaweiGroupOrAddress(rule.DestinationGroup, rule.Destination, rule.DstWildcard)
+		if rule.DstPort != "" {
+			line += " destination-port " + model.VendorPortSpec("huawei", proto, rule.DstPort)
+		}
+		if rule.ICMPType != "" {
+			line += " icmp-type " + toHuaweiICMPType(rule.ICMPType)
+			if rule.ICMPCode != "" {
+				line += " " + rule.ICMPCode
+			}
+		}
+		if rule.Established {
+			line += " tcp-flag established"
+		}
+		if rule.Precedence != "" {
+			line += " precedence " + rule.Precedence
+		}
+		if rule.TOS != "" {
+			line += " tos " + rule.TOS
+		}
+		if rule.DSCP != "" {
+			line += " dscp " + rule.DSCP
+		}
+	} else {
+		line = "source " + formatHuaweiAddress(rule.Source, rule.Wildcard)
+	}
+	if rule.Fragments {
+		line += " fragment"
+	}
+	if rule.Log {
+		line += " logging"
+	}
+	if rule.TimeRange != "" {
+		line += " time-range " + rule.TimeRange
+	}
+	return line
+}
+
+// toHuaweiICMPType переводит имена ICMP Cisco, которых нет в Huawei, в имена или номера типов.
+func toHuaweiICMPType(name string) string {
+	switch name {
+	case "packet-too-big":
+		return "fragmentneed-DFset"
+	case "unreachable":
+		return "3"
+	case "redirect":
+		return "5"
+	case "time-exceeded":
+		return "11"
+	}
+	return name
+}
+
+// formatHuaweiACLHeader открывает список. Номера назначены заранее, при
+// распределении номеров ACL; именованному списку без номера VRP выдаст его сам.
+func formatHuaweiACLHeader(acl model.ACL) string {
+	switch {
+	case acl.Name == "":
+		return fmt.Sprintf("acl number %d\n", acl.ID)
+	case acl.ID != 0:
+		return fmt.Sprintf("acl name %s %d\n", acl.Name, acl.ID)
+	case acl.Type == "standard" || acl.Type == "basic":
+		return fmt.Sprintf("acl name %s basic\n", acl.Name)
+	default:
+		return fmt.Sprintf("acl name %s advance\n", acl.Name)
+	}
+}
+
+func formatLineRange(l model.Line) string {
+	if l.Last != 0 {
+		return fmt.Sprintf("%s %d %d", l.Type, l.First, l.Last)
+	}
+	return fmt.Sprintf("%s %d", l.Type, l.First)
+}
+
+func writeCiscoTimeRanges(sb *strings.Builder, cfg *model.Config) {
+	for _, t := range cfg.TimeRanges {
+		sb.WriteString(fmt.Sprintf("time-range %s\n", t.Name))
+		for _, p := range t.Periodic {
+			days := make([]string, 0, len(p.Days))
+			for _, d := range p.Days {
+				days = append(days, toCiscoWeekday(d))
+			}
+			sb.WriteString(fmt.Sprintf(" periodic %s %s to %s\n", strings.Join(days, " "), p.Start, p.End))
+		}
+		if a := t.Absolute; a != nil {
+			startTime, endTime := absoluteTimes(*a)
+			line := " absolute"
+			if a.StartDate != "" {
+				line += fmt.Sprintf(" start %s %s", startTime, formatCiscoDate(a.StartDate))
+			}
+			if a.EndDate != "" {
+				line += fmt.Sprintf(" end %s %s", endTime, formatCiscoDate(a.EndDate))
+			}
+			sb.WriteString(line + "\n")
+		}
+		for _, raw := range t.Raw {
+			sb.WriteString(fmt.Sprintf(" %s\n", raw))
+		}
+		sb.WriteString(" exit\n")
+	}
+}
+
+func writeHuaweiTimeRanges(sb *strings.Builder, cfg *model.Config) {
+	for _, t := range cfg.TimeRanges {
+		for _, p := range t.Periodic {
+			days := make([]string, 0, len(p.Days))
+			for _, d := range p.Days {
+				days = append(days, toHuaweiWeekday(d))
+			}
+			sb.WriteString(fmt.Sprintf("time-range %s %s to %s %s\n", t.Name, p.Start, p.End, strings.Join(days, " ")))
+		}
+		if a := t.Absolute; a != nil {
+			// Huawei требует начало периода: без него берётся минимальная дата
+			startTime, endTime := absoluteTimes(*a)
+			startDate := a.StartDate
+			if startDate == "" {
+				startTime, startDate = "00:00", "1970-01-01"
+			}
+			line := fmt.Sprintf("time-range %s from %s %s", t.Name, startTime, strings.ReplaceAll(startDate, "-", "/"))
+			if a.EndDate != "" {
+				line += fmt.Sprintf(" to %s %s", endTime, strings.ReplaceAll(a.EndDate, "-", "/"))
+			}
+			sb.WriteString(line + "\n")
+		}
+		for _, raw := range t.Raw {
+			sb.WriteString(fmt.Sprintf("# unsupported time-range %s entry: %s\n", t.Name, raw))
+		}
+	}
+	if len(cfg.TimeRanges) > 0 {
+		sb.WriteString("\n")
+	}
+}
+
+var ciscoWeekdays = map[string]string{
+	"mon": "Monday", "tue": "Tuesday", "wed": "Wednesday", "thu": "Thursday",
+	"fri": "Friday", "sat": "Saturday", "sun": "Sunday",
+}
+
+// absoluteTimes возвращает время начала и конца периода; дата без времени
+// означает начало или конец суток, обе платформы требуют время явно.
+func absoluteTimes(a model.AbsoluteTime) (string, string) {
+	start, end := a.StartTime, a.EndTime
+	if start == "" {
+		start = "00:00"
+	}
+	if end == "" {
+		end = "23:59"
+	}
+	return start, end
+}
+
+func toCiscoWeekday(day string) string {
+	if name, ok := ciscoWeekdays[day]; ok {
+		return name
+	}
+	return day
+}
+
+func toHuaweiWeekday(day string) string {
+	switch day {
+	case "weekdays":
+		return "working-day"
+	case "weekend":
+		return "off-day"
+	case "daily":
+		return day
+	}
+	if len(day) == 3 {
+		return strings.ToUpper(day[:1]) + day[1:]
+	}
+	return day
+}
+
+// formatCiscoDate переводит "2026-01-31" в "31 January 2026".
+func formatCiscoDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format("02 January 2006")
+}
+
+func writeCiscoObjectGroups(sb *strings.Builder, cfg *model.Config) {
+	for _, g := range cfg.ObjectGroups {
+		sb.WriteString(fmt.Sprintf("object-group %s %s\n", g.Type, g.Name))
+		if g.Description != "" {
+			sb.WriteString(fmt.Sprintf(" description %s\n", g.Description))
+		}
+		for _, a := range g.Addresses {
+			switch {
+			case a.End != "":
+				sb.WriteString(fmt.Sprintf(" range %s %s\n", a.Address, a.End))
+			case a.Wildcard == "" || a.Wildcard == "0.0.0.0":
+				sb.WriteString(fmt.Sprintf(" host %s\n", a.Address))
+			default:
+				sb.WriteString(fmt.Sprintf(" %s %s\n", a.Address, model.WildcardToMask(a.Wildcard)))
+			}
+		}
+		for _, svc := range g.Services {
+			line := " " + model.ProtocolKeyword("cisco", svc.Protocol)
+			if svc.Protocol == "tcp-udp" {
+				line = " tcp-udp"
+			}
+			if svc.SrcPort != "" {
+				line += " source " + model.VendorPortSpec("cisco", svc.Protocol, svc.SrcPort)
+			}
+			if svc.DstPort != "" {
+				line += " " + model.VendorPortSpec("cisco", svc.Protocol, svc.DstPort)
+			}
+			if svc.ICMPType != "" {
+				line += " " + svc.ICMPType
+			}
+			sb.WriteString(line + "\n")
+		}
+		for _, nested := range g.Groups {
+			sb.WriteString(fmt.Sprintf(" group-object %s\n", nested))
+		}
+		sb.WriteString(" exit\n")
+	}
+}
+
+func writeHuaweiObjectGroups(sb *strings.Builder, cfg *model.Config) {
+	for _, g := range cfg.ObjectGroups {
+		if g.Type == "network" {
+			sb.WriteString(fmt.Sprintf("ip address-set %s type object\n", g.Name))
+		} else {
+			sb.WriteString(fmt.Sprintf("ip service-set %s type object\n", g.Name))
+		}
+		if g.Description != "" {
+			sb.WriteString(fmt.Sprintf(" description %s\n", g.Description))
+		}
+		idx := 0
+		for _, a := range g.Addresses {
+			if a.End != "" {
+				sb.WriteString(fmt.Sprintf(" address %d range %s %s\n", idx, a.Address, a.End))
+			} else {
+				length, _ := model.MaskLength(model.WildcardToMask(a.Wildcard))
+				if a.Wildcard == "" {
+					length = 32
+				}
+				sb.WriteString(fmt.Sprintf(" address %d %s mask %d\n", idx, a.Address, length))
+			}
+			idx++
+		}
+		for _, svc := range g.Services {
+			protos := []string{svc.Protocol}
+			if svc.Protocol == "tcp-udp" {
+				protos = []string{"tcp", "udp"}
+			}
+			for _, proto := range protos {
+				line := fmt.Sprintf(" service %d protocol %s", idx, model.ProtocolKeyword("huawei", proto))
+				if svc.SrcPort != "" {
+					line += " source-port " + formatHuaweiServicePort(svc.SrcPort)
+				}
+				if svc.DstPort != "" {
+					line += " destination-port " + formatHuaweiServicePort(svc.DstPort)
+				}
+				if svc.ICMPType != "" {
+					line += " icmp-type " + toHuaweiICMPType(svc.ICMPType)
+				}
+				sb.WriteString(line + "\n")
+				idx++
+			}
+		}
+		for _, nested := range g.Groups {
+			if g.Type == "network" {
+				sb.WriteString(fmt.Sprintf(" address %d address-set %s\n", idx, nested))
+			} else {
+				sb.WriteString(fmt.Sprintf(" service %d service-set %s\n", idx, nested))
+			}
+			idx++
+		}
+		sb.WriteString("quit\n\n")
+	}
+}
+
+// formatHuaweiServicePort переводит "eq 80" / "range 1 2" в синтаксис service-set ("80" / "1 to 2").
+func formatHuaweiServicePort(spec string) string {
+	parts := strings.Fields(spec)
+	switch {
+	case len(parts) == 2 && parts[0] == "eq":
+		return parts[1]
+	case len(parts) == 3 && parts[0] == "range":
+		return parts[1] + " to " + parts[2]
+	case len(parts) == 2 && parts[0] == "gt":
+		n, _ := strconv.Atoi(parts[1])
+		return fmt.Sprintf("%d to 65535", n+1)
+	case len(parts) == 2 && parts[0] == "lt":
+		n, _ := strconv.Atoi(parts[1])
+		return fmt.Sprintf("0 to %d", n-1)
+	}
+	return spec
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"converter/model"
@@ -12,139 +13,561 @@ func GenerateCisco(cfg *model.Config) string {
 	sb.WriteString("enable\n")
 	sb.WriteString("configure terminal\n")
 
-	for _, v := range cfg.Vlans {
-		sb.WriteString(fmt.Sprintf("vlan %d\n", v.ID))
-		if v.Name != "" {
-			sb.WriteString(fmt.Sprintf(" name %s\n", v.Name))
+	var blocks []configBlock
+	add := func(key string, deps []string, write func(sb *strings.Builder)) {
+		if text := renderBlock(write); text != "" {
+			blocks = append(blocks, configBlock{key: key, deps: deps, text: text})
 		}
-		sb.WriteString(" exit\n")
 	}
 
-	ospfByProcess := make(map[int][]model.OSPF)
-	var processOrder []int
-	for _, o := range cfg.OSPF {
-		if _, ok := ospfByProcess[o.ProcessID]; !ok {
-			processOrder = append(processOrder, o.ProcessID)
+	add("vlans", nil, func(sb *strings.Builder) {
+		for _, v := range cfg.Vlans {
+			sb.WriteString(fmt.Sprintf("vlan %d\n", v.ID))
+			if v.Name != "" {
+				sb.WriteString(fmt.Sprintf(" name %s\n", v.Name))
+			}
+			sb.WriteString(" exit\n")
+		}
+	})
+
+	add("services", nil, func(sb *strings.Builder) { writeCiscoServices(sb, cfg) })
+
+	add("dhcp", nil, func(sb *strings.Builder) { writeCiscoDHCP(sb, cfg) })
+
+	// ACL и всё, на что они ссылаются, создаются до фильтров, политик и NAT
+	add("time-ranges", nil, func(sb *strings.Builder) { writeCiscoTimeRanges(sb, cfg) })
+	add("object-groups", nil, func(sb *strings.Builder) { writeCiscoObjectGroups(sb, cfg) })
+	add("acls", []string{"time-ranges", "object-groups"}, func(sb *strings.Builder) { writeCiscoACLs(sb, cfg) })
+	add("policies", []string{"acls"}, func(sb *strings.Builder) { writeCiscoPolicies(sb, cfg) })
+	add("nat-pools", nil, func(sb *strings.Builder) { writeCiscoNATPools(sb, cfg) })
+
+	add("routing", []string{"policies"}, func(sb *strings.Builder) { writeCiscoRouting(sb, cfg) })
+	add("passive", passiveDeps(cfg), func(sb *strings.Builder) { writeCiscoPassiveInterfaces(sb, cfg) })
+
+	for _, i := range cfg.Interfaces {
+		add(interfaceKey(i.Name), interfaceDeps(i), func(sb *strings.Builder) { writeCiscoInterface(sb, cfg, i) })
+	}
+
+	add("routes", routeDeps(cfg), func(sb *strings.Builder) {
+		for _, r := range cfg.Routes {
+			sb.WriteString(formatCiscoRoute(r))
+		}
+		writeCiscoVRFRoutes(sb, cfg)
+	})
+	add("lines", []string{"acls"}, func(sb *strings.Builder) { writeCiscoLines(sb, cfg) })
+
+	// трансляции ссылаются на ACL, пулы и внешние интерфейсы
+	natDeps := []string{"acls", "nat-pools"}
+	for _, r := range cfg.NATRule {
+		if r.Outside != "" {
+			natDeps = append(natDeps, interfaceKey(r.Outside))
 		}
-		ospfByProcess[o.ProcessID] = append(ospfByProcess[o.ProcessID], o)
 	}
-	for _, pid := range processOrder {
-		sb.WriteString(fmt.Sprintf("router ospf %d\n", pid))
-		if cfg.OSPFRouterID != "" {
-			sb.WriteString(fmt.Sprintf(" router-id %s\n", cfg.OSPFRouterID))
+	for _, n := range cfg.StaticNAT {
+		if n.GlobalInterface != "" {
+			natDeps = append(natDeps, interfaceKey(n.GlobalInterface))
 		}
-		if cfg.OSPFPassiveDefault {
-			sb.WriteString(" passive-interface default\n")
-			for _, iface := range cfg.OSPFNoPassiveIfaces {
-				sb.WriteString(fmt.Sprintf(" no passive-interface %s\n", iface))
+	}
+	add("nat", natDeps, func(sb *strings.Builder) { writeCiscoNAT(sb, cfg) })
+
+	writeBlocks(&sb, blocks, "!")
+	sb.WriteString("end\n")
+
+	return sb.String()
+}
+
+func writeCiscoNAT(sb *strings.Builder, cfg *model.Config) {
+	for _, r := range cfg.NATRule {
+		sb.WriteString(formatCiscoNATRule(cfg, r))
+	}
+	for _, n := range cfg.StaticNAT {
+		sb.WriteString(formatCiscoStaticNAT(n))
+	}
+}
+
+func writeCiscoNATPools(sb *strings.Builder, cfg *model.Config) {
+	for _, pool := range cfg.NATPool {
+		mask := pool.Mask
+		if mask == "" {
+			mask = model.CoveringMask(pool.Start, pool.End)
+		}
+		sb.WriteString(fmt.Sprintf("ip nat pool %s %s %s netmask %s\n", pool.Name, pool.Start, pool.End, mask))
+	}
+}
+
+func writeCiscoDHCP(sb *strings.Builder, cfg *model.Config) {
+	for _, ex := range cfg.DHCP.Excluded {
+		sb.WriteString(fmt.Sprintf("ip dhcp excluded-address %s\n", formatDHCPExcluded(ex)))
+	}
+	for _, pool := range cfg.DHCP.Pools {
+		sb.WriteString(fmt.Sprintf("ip dhcp pool %s\n", pool.Name))
+		if pool.Network != "" {
+			sb.WriteString(fmt.Sprintf(" network %s %s\n", pool.Network, model.NormalizeMask(pool.Mask)))
+		}
+		if len(pool.DefaultRouters) > 0 {
+			sb.WriteString(fmt.Sprintf(" default-router %s\n", strings.Join(pool.DefaultRouters, " ")))
+		}
+		if len(pool.DNSServers) > 0 {
+			sb.WriteString(fmt.Sprintf(" dns-server %s\n", strings.Join(pool.DNSServers, " ")))
+		}
+		if pool.DomainName != "" {
+			sb.WriteString(fmt.Sprintf(" domain-name %s\n", pool.DomainName))
+		}
+		if pool.Lease != nil {
+			if pool.Lease.Infinite {
+				sb.WriteString(" lease infinite\n")
+			} else {
+				sb.WriteString(fmt.Sprintf(" lease %d %d %d\n", pool.Lease.Days, pool.Lease.Hours, pool.Lease.Minutes))
 			}
 		}
-		for _, o := range ospfByProcess[pid] {
-			sb.WriteString(fmt.Sprintf(" network %s %s area %s\n", o.Network, o.Wildcard, o.Area))
+		sb.WriteString(" exit\n")
+	}
+}
+
+func writeCiscoRouting(sb *strings.Builder, cfg *model.Config) {
+	for _, p := range cfg.OSPFProcesses {
+		writeCiscoOSPF(sb, p)
+	}
+	// Cisco поддерживает один процесс RIP: повторный "router rip" дополняет его.
+	for _, p := range cfg.RIP {
+		writeCiscoRIP(sb, p)
+	}
+	for _, p := range cfg.EIGRP {
+		writeCiscoEIGRP(sb, p)
+	}
+	for _, p := range cfg.ISIS {
+		writeCiscoISIS(sb, p)
+	}
+}
+
+func writeCiscoLines(sb *strings.Builder, cfg *model.Config) {
+	for _, l := range cfg.Lines {
+		sb.WriteString(fmt.Sprintf("line %s\n", formatLineRange(l)))
+		if l.ACLIn != "" {
+			sb.WriteString(fmt.Sprintf(" access-class %s in\n", ciscoACLRef(cfg, l.ACLIn)))
+		}
+		if l.ACLOut != "" {
+			sb.WriteString(fmt.Sprintf(" access-class %s out\n", ciscoACLRef(cfg, l.ACLOut)))
 		}
 		sb.WriteString(" exit\n")
 	}
+}
 
+func writeCiscoServices(sb *strings.Builder, cfg *model.Config) {
 	if cfg.STP.Mode != "" {
 		sb.WriteString(fmt.Sprintf("spanning-tree mode %s\n", cfg.STP.Mode))
 	}
-
 	if cfg.Service.SMTP {
 		sb.WriteString("ip smtp server\n")
 	}
 	if cfg.Service.FTP {
 		sb.WriteString("ip ftp server enable\n")
 	}
+}
 
-	for _, i := range cfg.Interfaces {
-		sb.WriteString(fmt.Sprintf("interface %s\n", i.Name))
-		if i.TrunkVlans != "" {
-			sb.WriteString(" switchport mode trunk\n")
-			sb.WriteString(fmt.Sprintf(" switchport trunk allowed vlan %s\n", i.TrunkVlans))
-		}
-		if i.Description != "" {
-			sb.WriteString(fmt.Sprintf(" description %s\n", i.Description))
-		}
-		if i.Vlan != 0 && isCiscoSubinterface(i.Name) {
-			sb.WriteString(fmt.Sprintf(" encapsulation dot1Q %d\n", i.Vlan))
-		} else if i.Vlan != 0 {
-			sb.WriteString(fmt.Sprintf(" switchport access vlan %d\n", i.Vlan))
+func writeCiscoInterface(sb *strings.Builder, cfg *model.Config, i model.Interface) {
+	sb.WriteString(fmt.Sprintf("interface %s\n", i.Name))
+	if i.TrunkVlans != "" {
+		sb.WriteString(" switchport mode trunk\n")
+		sb.WriteString(fmt.Sprintf(" switchport trunk allowed vlan %s\n", i.TrunkVlans))
+	}
+	if i.Description != "" {
+		sb.WriteString(fmt.Sprintf(" description %s\n", i.Description))
+	}
+	if i.Vlan != 0 && isCiscoSubinterface(i.Name) {
+		sb.WriteString(fmt.Sprintf(" encapsulation dot1Q %d\n", i.Vlan))
+	} else if i.Vlan != 0 {
+		sb.WriteString(fmt.Sprintf(" switchport access vlan %d\n", i.Vlan))
+	}
+	if i.Bandwidth != 0 {
+		sb.WriteString(fmt.Sprintf(" bandwidth %d\n", i.Bandwidth))
+	}
+	if i.IP != "" {
+		sb.WriteString(fmt.Sprintf(" ip address %s\n", i.IP))
+	}
+	writeCiscoInterfaceServices(sb, cfg, i)
+	if i.ACLIn != "" {
+		sb.WriteString(fmt.Sprintf(" ip access-group %s in\n", ciscoACLRef(cfg, i.ACLIn)))
+	}
+	if i.ACLOut != "" {
+		sb.WriteString(fmt.Sprintf(" ip access-group %s out\n", ciscoACLRef(cfg, i.ACLOut)))
+	}
+	if i.NAT == "inside" || i.NAT == "outside" {
+		sb.WriteString(fmt.Sprintf(" ip nat %s\n", i.NAT))
+	}
+	sb.WriteString(" exit\n")
+}
+
+// writeCiscoInterfaceServices пишет relay DHCP и настройки протоколов маршрутизации интерфейса.
+func writeCiscoInterfaceServices(sb *strings.Builder, cfg *model.Config, i model.Interface) {
+	for _, server := range findDHCPRelayServers(cfg, i.Name) {
+		sb.WriteString(fmt.Sprintf(" ip helper-address %s\n", server))
+	}
+	if i.OSPF != nil {
+		writeCiscoInterfaceOSPF(sb, *i.OSPF)
+	}
+	if i.ISIS != nil {
+		writeCiscoInterfaceISIS(sb, *i.ISIS)
+	}
+}
+
+// ospfKeyID возвращает номер ключа MD5; без явного номера используется ключ 1.
+func ospfKeyID(o model.InterfaceOSPF) int {
+	if o.AuthKeyID == 0 {
+		return 1
+	}
+	return o.AuthKeyID
+}
+
+func writeCiscoInterfaceOSPF(sb *strings.Builder, o model.InterfaceOSPF) {
+	if o.ProcessID != 0 && o.Area != "" {
+		sb.WriteString(fmt.Sprintf(" ip ospf %d area %s\n", o.ProcessID, o.Area))
+	}
+	if o.NetworkType != "" {
+		sb.WriteString(fmt.Sprintf(" ip ospf network %s\n", o.NetworkType))
+	}
+	if o.Cost != 0 {
+		sb.WriteString(fmt.Sprintf(" ip ospf cost %d\n", o.Cost))
+	}
+	if o.Priority != nil {
+		sb.WriteString(fmt.Sprintf(" ip ospf priority %d\n", *o.Priority))
+	}
+	if o.HelloInterval != 0 {
+		sb.WriteString(fmt.Sprintf(" ip ospf hello-interval %d\n", o.HelloInterval))
+	}
+	if o.DeadInterval != 0 {
+		sb.WriteString(fmt.Sprintf(" ip ospf dead-interval %d\n", o.DeadInterval))
+	}
+	if o.AuthKeyEncrypted {
+		sb.WriteString(" ! OSPF key is stored encrypted on the source device, set it manually\n")
+	}
+	switch o.AuthMode {
+	case "md5":
+		sb.WriteString(" ip ospf authentication message-digest\n")
+		if o.AuthKey != "" && !o.AuthKeyEncrypted {
+			sb.WriteString(fmt.Sprintf(" ip ospf message-digest-key %d md5 %s\n", ospfKeyID(o), o.AuthKey))
 		}
-		if i.IP != "" {
-			sb.WriteString(fmt.Sprintf(" ip address %s\n", i.IP))
+	case "simple":
+		sb.WriteString(" ip ospf authentication\n")
+		if o.AuthKey != "" && !o.AuthKeyEncrypted {
+			sb.WriteString(fmt.Sprintf(" ip ospf authentication-key %s\n", o.AuthKey))
 		}
-		sb.WriteString(" exit\n")
+	case "key-chain":
+		sb.WriteString(fmt.Sprintf(" ip ospf authentication key-chain %s\n", o.KeyChain))
 	}
+}
 
-	for _, r := range cfg.Routes {
-		sb.WriteString(fmt.Sprintf("ip route %s %s %s\n", r.Destination, r.Mask, r.Gateway))
+func writeCiscoOSPF(sb *strings.Builder, p model.OSPFProcess) {
+	sb.WriteString(fmt.Sprintf("router ospf %d\n", p.ProcessID))
+	if p.RouterID != "" {
+		sb.WriteString(fmt.Sprintf(" router-id %s\n", p.RouterID))
+	}
+	if p.ReferenceBandwidth != 0 {
+		sb.WriteString(fmt.Sprintf(" auto-cost reference-bandwidth %d\n", p.ReferenceBandwidth))
 	}
-	for _, acl := range cfg.ACLs {
-		ciscoACLID := mapACLIDToCisco(acl.ID, acl.Type)
-		for _, rule := range acl.Rules {
-			if rule.Raw != "" {
-				sb.WriteString(fmt.Sprintf("access-list %d %s\n", ciscoACLID, rule.Raw))
-				continue
+	if p.SPFTimers != nil {
+		sb.WriteString(fmt.Sprintf(" timers throttle spf %d %d %d\n", p.SPFTimers.Start, p.SPFTimers.Hold, p.SPFTimers.Max))
+	}
+	for _, a := range p.Areas {
+		switch a.Type {
+		case "stub":
+			sb.WriteString(fmt.Sprintf(" area %s stub\n", a.ID))
+		case "totally-stubby":
+			sb.WriteString(fmt.Sprintf(" area %s stub no-summary\n", a.ID))
+		case "nssa", "totally-nssa":
+			line := fmt.Sprintf(" area %s nssa", a.ID)
+			if a.NSSADefaultOriginate {
+				line += " default-information-originate"
 			}
-			action := rule.Action
-			if action == "" {
-				action = "permit"
+			if a.Type == "totally-nssa" {
+				line += " no-summary"
 			}
-			if isExtendedACLRule(rule, acl.Type) {
-				proto := rule.Protocol
-				if proto == "" {
-					proto = "ip"
-				}
-				line := fmt.Sprintf("access-list %d %s %s %s", ciscoACLID, action, proto, formatCiscoAddress(rule.Source, rule.Wildcard))
-				if rule.SrcPort != "" {
-					line += " " + rule.SrcPort
-				}
-				line += " " + formatCiscoAddress(rule.Destination, rule.DstWildcard)
-				if rule.DstPort != "" {
-					line += " " + rule.DstPort
-				}
-				sb.WriteString(line + "\n")
-			} else {
-				sb.WriteString(fmt.Sprintf("access-list %d %s %s\n", ciscoACLID, action, formatCiscoAddress(rule.Source, rule.Wildcard)))
+			sb.WriteString(line + "\n")
+		}
+		if a.DefaultCost != 0 {
+			sb.WriteString(fmt.Sprintf(" area %s default-cost %d\n", a.ID, a.DefaultCost))
+		}
+		for _, r := range a.Ranges {
+			line := fmt.Sprintf(" area %s range %s %s", a.ID, r.Network, model.NormalizeMask(r.Mask))
+			if r.NotAdvertise {
+				line += " not-advertise"
+			}
+			if r.Cost != 0 {
+				line += fmt.Sprintf(" cost %d", r.Cost)
 			}
+			sb.WriteString(line + "\n")
 		}
 	}
-	for _, r := range cfg.NATRule {
-		ciscoACL := mapACLIDToCisco(r.ACLID, findACLType(cfg, r.ACLID))
-		line := fmt.Sprintf("ip nat inside source list %d interface %s", ciscoACL, r.Outside)
-		if r.Overload {
-			line += " overload"
+	for _, n := range p.Networks {
+		sb.WriteString(fmt.Sprintf(" network %s %s area %s\n", n.Network, n.Wildcard, n.Area))
+	}
+	for _, r := range p.Redistribute {
+		sb.WriteString(formatCiscoRedistribution(r, true))
+	}
+	if d := p.DefaultInformation; d != nil {
+		line := " default-information originate"
+		if d.Always {
+			line += " always"
+		}
+		if d.Metric != 0 {
+			line += fmt.Sprintf(" metric %d", d.Metric)
+		}
+		if d.MetricType != 0 {
+			line += fmt.Sprintf(" metric-type %d", d.MetricType)
+		}
+		if d.RouteMap != "" {
+			line += " route-map " + d.RouteMap
 		}
 		sb.WriteString(line + "\n")
 	}
-	for _, n := range cfg.NAT {
-		sb.WriteString(fmt.Sprintf("interface %s\n", n.Inside))
-		sb.WriteString(" ip nat inside\n")
-		sb.WriteString(" exit\n")
-		sb.WriteString(fmt.Sprintf("interface %s\n", n.Outside))
-		sb.WriteString(" ip nat outside\n")
+	sb.WriteString(" exit\n")
+}
+
+func writeCiscoRIP(sb *strings.Builder, p model.RIPProcess) {
+	sb.WriteString("router rip\n")
+	if p.Version != 0 {
+		sb.WriteString(fmt.Sprintf(" version %d\n", p.Version))
+	}
+	if p.NoAutoSummary {
+		sb.WriteString(" no auto-summary\n")
+	}
+	for _, n := range p.Networks {
+		sb.WriteString(fmt.Sprintf(" network %s\n", n))
+	}
+	for _, r := range p.Redistribute {
+		sb.WriteString(formatCiscoRedistribution(r, false))
+	}
+	if d := p.DefaultInformation; d != nil {
+		line := " default-information originate"
+		if d.RouteMap != "" {
+			line += " route-map " + d.RouteMap
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString(" exit\n")
+}
+
+func writeCiscoEIGRP(sb *strings.Builder, p model.EIGRPProcess) {
+	sb.WriteString(fmt.Sprintf("router eigrp %d\n", p.AS))
+	if p.RouterID != "" {
+		sb.WriteString(fmt.Sprintf(" eigrp router-id %s\n", p.RouterID))
+	}
+	if p.NoAutoSummary {
+		sb.WriteString(" no auto-summary\n")
+	}
+	for _, n := range p.Networks {
+		if n.Wildcard != "" {
+			sb.WriteString(fmt.Sprintf(" network %s %s\n", n.Network, n.Wildcard))
+		} else {
+			sb.WriteString(fmt.Sprintf(" network %s\n", n.Network))
+		}
+	}
+	for _, r := range p.Redistribute {
+		sb.WriteString(formatCiscoRedistribution(r, false))
+	}
+	sb.WriteString(" exit\n")
+}
+
+func writeCiscoISIS(sb *strings.Builder, p model.ISISProcess) {
+	if p.Tag != "" {
+		sb.WriteString(fmt.Sprintf("router isis %s\n", p.Tag))
+	} else {
+		sb.WriteString("router isis\n")
+	}
+	for _, net := range p.NET {
+		sb.WriteString(fmt.Sprintf(" net %s\n", net))
+	}
+	if p.Level != "" {
+		sb.WriteString(fmt.Sprintf(" is-type %s\n", toCiscoISISLevel(p.Level)))
+	}
+	if p.MetricStyle != "" {
+		sb.WriteString(fmt.Sprintf(" metric-style %s\n", p.MetricStyle))
+	}
+	for _, r := range p.Redistribute {
+		sb.WriteString(formatCiscoRedistribution(r, false))
+	}
+	if p.DefaultInformation != nil {
+		line := " default-information originate"
+		if p.DefaultInformation.RouteMap != "" {
+			line += " route-map " + p.DefaultInformation.RouteMap
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString(" exit\n")
+}
+
+func writeCiscoInterfaceISIS(sb *strings.Builder, o model.InterfaceISIS) {
+	if o.Tag != "" {
+		sb.WriteString(fmt.Sprintf(" ip router isis %s\n", o.Tag))
+	} else {
+		sb.WriteString(" ip router isis\n")
+	}
+	if o.CircuitType != "" {
+		sb.WriteString(fmt.Sprintf(" isis circuit-type %s\n", toCiscoISISLevel(o.CircuitType)))
+	}
+	if o.Metric != 0 {
+		sb.WriteString(fmt.Sprintf(" isis metric %d\n", o.Metric))
+	}
+	if o.PointToPoint {
+		sb.WriteString(" isis network point-to-point\n")
+	}
+}
+
+// toCiscoISISLevel возвращает "level-2-only" для "level-2".
+func toCiscoISISLevel(level string) string {
+	if level == "level-2" {
+		return "level-2-only"
+	}
+	return level
+}
+
+// writeCiscoPassiveInterfaces повторно входит в процессы маршрутизации и задаёт
+// passive-interface: эти команды ссылаются на интерфейсы и идут после их блоков.
+func writeCiscoPassiveInterfaces(sb *strings.Builder, cfg *model.Config) {
+	section := func(header string, passiveDefault bool, passive, noPassive []string) {
+		if !passiveDefault && len(passive) == 0 {
+			return
+		}
+		sb.WriteString(header + "\n")
+		writeCiscoPassive(sb, passiveDefault, passive, noPassive)
 		sb.WriteString(" exit\n")
 	}
-	sb.WriteString("end\n")
+	for _, p := range cfg.OSPFProcesses {
+		section(fmt.Sprintf("router ospf %d", p.ProcessID), p.PassiveDefault, p.PassiveInterfaces, p.NoPassiveInterfaces)
+	}
+	for _, p := range cfg.RIP {
+		section("router rip", p.PassiveDefault, p.PassiveInterfaces, p.NoPassiveInterfaces)
+	}
+	for _, p := range cfg.EIGRP {
+		section(fmt.Sprintf("router eigrp %d", p.AS), p.PassiveDefault, p.PassiveInterfaces, p.NoPassiveInterfaces)
+	}
+	for _, p := range cfg.ISIS {
+		section(strings.TrimSpace("router isis "+p.Tag), false, p.PassiveInterfaces, nil)
+	}
+}
 
-	return sb.String()
+func writeCiscoPassive(sb *strings.Builder, passiveDefault bool, passive, noPassive []string) {
+	if passiveDefault {
+		sb.WriteString(" passive-interface default\n")
+		for _, iface := range noPassive {
+			sb.WriteString(fmt.Sprintf(" no passive-interface %s\n", iface))
+		}
+		return
+	}
+	for _, iface := range passive {
+		sb.WriteString(fmt.Sprintf(" passive-interface %s\n", iface))
+	}
 }
 
-func mapACLIDToCisco(id int, aclType string) int {
-	if aclType == "advanced" && id >= 3000 && id <= 3999 {
-		return id - 1000
+// formatCiscoRedistribution формирует "redistribute"; subnets нужен только для OSPF.
+func formatCiscoRedistribution(r model.Redistribution, subnets bool) string {
+	line := " redistribute " + r.Protocol
+	if r.Process != "" {
+		line += " " + r.Process
+	}
+	if r.EIGRPMetric != "" {
+		line += " metric " + r.EIGRPMetric
+	} else if r.Metric != 0 {
+		line += fmt.Sprintf(" metric %d", r.Metric)
+	}
+	if r.MetricType != 0 {
+		line += fmt.Sprintf(" metric-type %d", r.MetricType)
 	}
-	if (aclType == "basic" || aclType == "standard" || aclType == "") && id >= 2000 && id <= 2999 {
-		return id - 2000
+	if subnets {
+		line += " subnets"
 	}
-	return id
+	if r.Tag != 0 {
+		line += fmt.Sprintf(" tag %d", r.Tag)
+	}
+	if r.RouteMap != "" {
+		line += " route-map " + r.RouteMap
+	}
+	return line + "\n"
+}
+
+// writeCiscoVRFRoutes переносит маршруты VRF строками исходной конфигурации,
+// если она тоже Cisco; строки Huawei остаются комментариями.
+func writeCiscoVRFRoutes(sb *strings.Builder, cfg *model.Config) {
+	for _, line := range cfg.VRFRoutes {
+		if cfg.DeviceType == "cisco" {
+			sb.WriteString(line + "\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("! VRF route not converted: %s\n", line))
+		}
+	}
+}
+
+func formatCiscoRoute(r model.Route) string {
+	line := fmt.Sprintf("ip route %s %s", r.Destination, r.Mask)
+	if r.Interface != "" {
+		line += " " + r.Interface
+	}
+	if r.Gateway != "" {
+		line += " " + r.Gateway
+	}
+	if r.Distance != 0 {
+		line += fmt.Sprintf(" %d", r.Distance)
+	}
+	if r.Tag != 0 {
+		line += fmt.Sprintf(" tag %d", r.Tag)
+	}
+	if r.Permanent {
+		line += " permanent"
+	} else if r.Track != "" {
+		if _, err := strconv.Atoi(r.Track); err == nil {
+			line += " track " + r.Track
+		} else {
+			line = fmt.Sprintf("! track %q not converted\n", r.Track) + line
+		}
+	}
+	if r.Name != "" {
+		// имя маршрута Cisco — одно слово
+		name := strings.Join(strings.Fields(r.Name), "_")
+		if name != r.Name {
+			line = fmt.Sprintf("! route name %q changed to %s\n", r.Name, name) + line
+		}
+		line += " name " + name
+	}
+	return line + "\n"
+}
+
+func formatCiscoNATRule(cfg *model.Config, r model.NATPolicy) string {
+	ciscoACL := ciscoACLRef(cfg, r.ACLRef())
+	line := fmt.Sprintf("ip nat inside source list %s interface %s", ciscoACL, r.Outside)
+	if r.Pool != "" {
+		line = fmt.Sprintf("ip nat inside source list %s pool %s", ciscoACL, r.Pool)
+	}
+	if r.Overload {
+		line += " overload"
+	}
+	return line + "\n"
+}
+
+func formatCiscoStaticNAT(n model.StaticNAT) string {
+	line := "ip nat inside source static"
+	if n.Protocol != "" {
+		line += fmt.Sprintf(" %s %s %s", n.Protocol, n.InsideAddress, n.InsidePort)
+	} else {
+		line += " " + n.InsideAddress
+	}
+	if n.GlobalInterface != "" {
+		line += " interface " + n.GlobalInterface
+	} else {
+		line += " " + n.GlobalAddress
+	}
+	if n.Protocol != "" {
+		line += " " + n.GlobalPort
+	}
+	return line + "\n"
 }
 
 func isExtendedACLRule(rule model.ACLRule, aclType string) bool {
 	if aclType == "extended" || aclType == "advanced" {
 		return true
 	}
-	return rule.Protocol != "" || rule.Destination != "" || rule.DstPort != "" || rule.SrcPort != ""
+	return rule.Protocol != "" || rule.Destination != "" || rule.DstPort != "" || rule.SrcPort != "" ||
+		rule.ICMPType != "" || rule.Established || rule.DSCP != "" || rule.Precedence != "" || rule.TOS != "" ||
+		rule.SourceGroup != "" || rule.DestinationGroup != "" || rule.ServiceGroup != ""
 }
 
 func formatCiscoAddress(addr, wildcard string) string {
@@ -157,15 +580,6 @@ func formatCiscoAddress(addr, wildcard string) string {
 	return addr + " " + wildcard
 }
 
-func findACLType(cfg *model.Config, id int) string {
-	for _, acl := range cfg.ACLs {
-		if acl.ID == id {
-			return acl.Type
-		}
-	}
-	return ""
-}
-
 func isCiscoSubinterface(name string) bool {
 	return strings.Contains(name, ".")
 }
@@ -0,0 +1,790 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"converter/model"
+)
+
+// GenerateCiscoDelta формирует команды, переводящие работающую конфигурацию running
+// в желаемую desired. Сравниваются VLAN, интерфейсы (описание, адрес, access/trunk
+// VLAN, полоса, привязки ACL, роль NAT, relay DHCP, настройки OSPF и IS-IS),
+// статические маршруты, сети OSPF, записи ACL, пулы и правила NAT.
+// Сначала создаются объекты, на которые ссылаются другие (VLAN, ACL, пулы NAT), затем
+// меняются интерфейсы и добавляются маршруты, и только после этого удаляется лишнее.
+// Вторым значением возвращаются разделы, которые различаются, но в дельту не входят.
+func GenerateCiscoDelta(running, desired *model.Config) (string, []string) {
+	var sb strings.Builder
+	sb.WriteString("configure terminal\n")
+
+	oldVlans := vlansByID(running)
+	for _, v := range desired.Vlans {
+		old, ok := oldVlans[v.ID]
+		if ok && old.Name == v.Name {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("vlan %d\n", v.ID))
+		if v.Name != "" {
+			sb.WriteString(fmt.Sprintf(" name %s\n", v.Name))
+		} else if ok {
+			sb.WriteString(" no name\n")
+		}
+		sb.WriteString(" exit\n")
+	}
+
+	oldPools, newPools := renderBlock(func(sb *strings.Builder) { writeCiscoNATPools(sb, running) }),
+		renderBlock(func(sb *strings.Builder) { writeCiscoNATPools(sb, desired) })
+	writeGlobalLineDelta(&sb, oldPools, newPools, "!", 4, undoCiscoLine, false)
+	writeCiscoACLDelta(&sb, running, desired, false)
+
+	oldIfaces := interfacesByName(running)
+	for _, i := range desired.Interfaces {
+		writeCiscoInterfaceDelta(&sb, running, desired, oldIfaces[i.Name], i)
+	}
+
+	oldRoutes, newRoutes := routeLines(running, formatCiscoRoute), routeLines(desired, formatCiscoRoute)
+	for _, line := range newRoutes {
+		if !containsString(oldRoutes, line) {
+			sb.WriteString(line)
+		}
+	}
+	writeCiscoOSPFNetworkDelta(&sb, running, desired, false)
+	oldNAT, newNAT := renderBlock(func(sb *strings.Builder) { writeCiscoNAT(sb, running) }),
+		renderBlock(func(sb *strings.Builder) { writeCiscoNAT(sb, desired) })
+	writeGlobalLineDelta(&sb, oldNAT, newNAT, "!", 0, undoCiscoLine, false)
+
+	writeGlobalLineDelta(&sb, oldNAT, newNAT, "!", 0, undoCiscoLine, true)
+	for _, line := range oldRoutes {
+		if !containsString(newRoutes, line) {
+			sb.WriteString("no " + lastLine(line) + "\n")
+		}
+	}
+	writeCiscoOSPFNetworkDelta(&sb, desired, running, true)
+	newIfaces := interfacesByName(desired)
+	for _, i := range running.Interfaces {
+		if _, ok := newIfaces[i.Name]; !ok && !isLogicalInterface(i.Name) {
+			writeCiscoInterfaceDelta(&sb, running, desired, i, model.Interface{Name: i.Name})
+		}
+	}
+
+	for _, i := range running.Interfaces {
+		if _, ok := newIfaces[i.Name]; !ok && isLogicalInterface(i.Name) {
+			sb.WriteString(fmt.Sprintf("no interface %s\n", i.Name))
+		}
+	}
+	writeGlobalLineDelta(&sb, oldPools, newPools, "!", 4, undoCiscoLine, true)
+	writeCiscoACLDelta(&sb, running, desired, true)
+	newVlans := vlansByID(desired)
+	for _, v := range running.Vlans {
+		if _, ok := newVlans[v.ID]; !ok {
+			sb.WriteString(fmt.Sprintf("no vlan %d\n", v.ID))
+		}
+	}
+
+	sb.WriteString("end\n")
+	return sb.String(), changedSections(ciscoDeltaSections, running, desired)
+}
+
+// GenerateHuaweiDelta — то же, что GenerateCiscoDelta, для Huawei: удаление через "undo";
+// правила NAT сравниваются в блоках внешних интерфейсов.
+func GenerateHuaweiDelta(running, desired *model.Config) (string, []string) {
+	var sb strings.Builder
+	sb.WriteString("system-view\n")
+
+	oldVlans := vlansByID(running)
+	for _, v := range desired.Vlans {
+		old, ok := oldVlans[v.ID]
+		if ok && old.Name == v.Name {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("vlan %d\n", v.ID))
+		if v.Name != "" {
+			sb.WriteString(fmt.Sprintf(" description %s\n", v.Name))
+		} else if ok {
+			sb.WriteString(" undo description\n")
+		}
+		sb.WriteString("quit\n")
+	}
+
+	oldPools, newPools := renderBlock(func(sb *strings.Builder) { writeHuaweiNATPools(sb, running) }),
+		renderBlock(func(sb *strings.Builder) { writeHuaweiNATPools(sb, desired) })
+	writeGlobalLineDelta(&sb, oldPools, newPools, "#", 3, undoHuaweiLine, false)
+	writeHuaweiACLDelta(&sb, running, desired, false)
+
+	oldIfaces := interfacesByName(running)
+	for _, i := range desired.Interfaces {
+		writeHuaweiInterfaceDelta(&sb, running, desired, oldIfaces[i.Name], i)
+	}
+	for _, name := range natOnlyInterfaces(running, desired) {
+		writeHuaweiInterfaceDelta(&sb, running, desired, model.Interface{Name: name}, model.Interface{Name: name})
+	}
+
+	oldRoutes, newRoutes := routeLines(running, formatHuaweiRoute), routeLines(desired, formatHuaweiRoute)
+	for _, line := range newRoutes {
+		if !containsString(oldRoutes, line) {
+			sb.WriteString(line)
+		}
+	}
+	writeHuaweiOSPFNetworkDelta(&sb, running, desired, false)
+	for _, line := range oldRoutes {
+		if !containsString(newRoutes, line) {
+			sb.WriteString("undo " + lastLine(line) + "\n")
+		}
+	}
+	writeHuaweiOSPFNetworkDelta(&sb, desired, running, true)
+	newIfaces := interfacesByName(desired)
+	for _, i := range running.Interfaces {
+		if _, ok := newIfaces[i.Name]; !ok && !isLogicalInterface(i.Name) {
+			writeHuaweiInterfaceDelta(&sb, running, desired, i, model.Interface{Name: i.Name})
+		}
+	}
+
+	for _, i := range running.Interfaces {
+		if _, ok := newIfaces[i.Name]; !ok && isLogicalInterface(i.Name) {
+			sb.WriteString(fmt.Sprintf("undo interface %s\n", toHuaweiIfaceName(i.Name)))
+		}
+	}
+	writeGlobalLineDelta(&sb, oldPools, newPools, "#", 3, undoHuaweiLine, true)
+	writeHuaweiACLDelta(&sb, running, desired, true)
+	newVlans := vlansByID(desired)
+	for _, v := range running.Vlans {
+		if _, ok := newVlans[v.ID]; !ok {
+			sb.WriteString(fmt.Sprintf("undo vlan %d\n", v.ID))
+		}
+	}
+
+	sb.WriteString("return\n")
+	return sb.String(), changedSections(huaweiDeltaSections, running, desired)
+}
+
+// writeCiscoInterfaceDelta выводит изменённые атрибуты интерфейса; блок
+// "interface" пишется только если есть что менять.
+func writeCiscoInterfaceDelta(sb *strings.Builder, running, desired *model.Config, old, i model.Interface) {
+	var lines []string
+	add := func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+	if old.Description != i.Description {
+		if i.Description == "" {
+			add(" no description")
+		} else {
+			add(" description %s", i.Description)
+		}
+	}
+	if old.TrunkVlans != i.TrunkVlans {
+		switch {
+		case i.TrunkVlans == "":
+			add(" no switchport trunk allowed vlan")
+		case old.TrunkVlans == "":
+			add(" switchport mode trunk")
+			fallthrough
+		default:
+			add(" switchport trunk allowed vlan %s", i.TrunkVlans)
+		}
+	}
+	if old.Vlan != i.Vlan {
+		switch {
+		case isCiscoSubinterface(i.Name) && i.Vlan != 0:
+			add(" encapsulation dot1Q %d", i.Vlan)
+		case isCiscoSubinterface(i.Name):
+			add(" no encapsulation dot1Q %d", old.Vlan)
+		case i.Vlan != 0:
+			add(" switchport access vlan %d", i.Vlan)
+		default:
+			add(" no switchport access vlan")
+		}
+	}
+	if old.Bandwidth != i.Bandwidth {
+		if i.Bandwidth == 0 {
+			add(" no bandwidth")
+		} else {
+			add(" bandwidth %d", i.Bandwidth)
+		}
+	}
+	if old.IP != i.IP {
+		if i.IP == "" {
+			add(" no ip address")
+		} else {
+			add(" ip address %s", i.IP)
+		}
+	}
+	for _, dir := range []string{"in", "out"} {
+		oldRef, newRef := old.ACLIn, i.ACLIn
+		if dir == "out" {
+			oldRef, newRef = old.ACLOut, i.ACLOut
+		}
+		if oldRef != "" {
+			oldRef = ciscoACLRef(running, oldRef)
+		}
+		if newRef != "" {
+			newRef = ciscoACLRef(desired, newRef)
+		}
+		switch {
+		case oldRef == newRef:
+		case newRef == "":
+			add(" no ip access-group %s %s", oldRef, dir)
+		default:
+			add(" ip access-group %s %s", newRef, dir)
+		}
+	}
+	removed, added := lineChanges(ciscoInterfaceExtras(running, old), ciscoInterfaceExtras(desired, i), "!")
+	for _, line := range removed {
+		add(" %s", undoCiscoLine(line))
+	}
+	for _, line := range added {
+		add(" %s", line)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("interface %s\n", i.Name))
+	sb.WriteString(strings.Join(lines, "\n") + "\n")
+	sb.WriteString(" exit\n")
+}
+
+func writeHuaweiInterfaceDelta(sb *strings.Builder, running, desired *model.Config, old, i model.Interface) {
+	var lines []string
+	add := func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+	if old.Description != i.Description {
+		if i.Description == "" {
+			add(" undo description")
+		} else {
+			add(" description %s", i.Description)
+		}
+	}
+	if old.Vlan != i.Vlan {
+		switch {
+		case isHuaweiSubinterface(i.Name) && i.Vlan != 0:
+			add(" vlan-type dot1q %d", i.Vlan)
+		case isHuaweiSubinterface(i.Name):
+			add(" undo vlan-type dot1q")
+		case i.Vlan != 0:
+			if old.Vlan == 0 {
+				add(" port link-type access")
+			}
+			add(" port default vlan %d", i.Vlan)
+		default:
+			add(" undo port default vlan")
+		}
+	}
+	// allow-pass добавляет VLAN к списку, поэтому старый список сначала удаляется
+	if old.TrunkVlans != i.TrunkVlans {
+		if old.TrunkVlans != "" {
+			add(" undo port trunk allow-pass vlan %s", old.TrunkVlans)
+		}
+		if i.TrunkVlans != "" {
+			if old.TrunkVlans == "" {
+				add(" port link-type trunk")
+			}
+			add(" port trunk allow-pass vlan %s", i.TrunkVlans)
+		}
+	}
+	if old.Bandwidth != i.Bandwidth {
+		if i.Bandwidth == 0 {
+			add(" undo bandwidth")
+		} else {
+			add(" bandwidth %d", max(1, i.Bandwidth/1000))
+		}
+	}
+	if old.IP != i.IP {
+		if i.IP == "" {
+			add(" undo ip address")
+		} else {
+			add(" ip address %s", i.IP)
+		}
+	}
+	for _, dir := range []string{"inbound", "outbound"} {
+		oldRef, newRef := old.ACLIn, i.ACLIn
+		if dir == "outbound" {
+			oldRef, newRef = old.ACLOut, i.ACLOut
+		}
+		if oldRef != "" {
+			oldRef = huaweiACLRef(running, oldRef)
+		}
+		if newRef != "" {
+			newRef = huaweiACLRef(desired, newRef)
+		}
+		switch {
+		case oldRef == newRef:
+		case newRef == "":
+			add(" undo traffic-filter %s", dir)
+		default:
+			if oldRef != "" {
+				add(" undo traffic-filter %s", dir)
+			}
+			add(" traffic-filter %s acl %s", dir, newRef)
+		}
+	}
+	removed, added := lineChanges(huaweiInterfaceExtras(running, old), huaweiInterfaceExtras(desired, i), "#")
+	for _, line := range removed {
+		add(" %s", undoHuaweiLine(line))
+	}
+	for _, line := range added {
+		add(" %s", line)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("interface %s\n", toHuaweiIfaceName(i.Name)))
+	sb.WriteString(strings.Join(lines, "\n") + "\n")
+	sb.WriteString("quit\n")
+}
+
+// ciscoInterfaceExtras собирает строки интерфейса, которые сравниваются построчно:
+// relay DHCP, настройки OSPF и IS-IS, роль NAT.
+func ciscoInterfaceExtras(cfg *model.Config, i model.Interface) string {
+	return renderBlock(func(sb *strings.Builder) {
+		if i.Name == "" {
+			return
+		}
+		writeCiscoInterfaceServices(sb, cfg, i)
+		if i.NAT == "inside" || i.NAT == "outside" {
+			sb.WriteString(fmt.Sprintf(" ip nat %s\n", i.NAT))
+		}
+	})
+}
+
+// huaweiInterfaceExtras — то же для Huawei: DHCP, OSPF, IS-IS и правила NAT,
+// которые Huawei задаёт на внешнем интерфейсе.
+func huaweiInterfaceExtras(cfg *model.Config, i model.Interface) string {
+	return renderBlock(func(sb *strings.Builder) {
+		if i.Name == "" {
+			return
+		}
+		writeHuaweiInterfaceServices(sb, cfg, i)
+		_, natLines, _ := huaweiNATInterfaces(cfg, huaweiNATPoolIDs(cfg))
+		for _, line := range natLines[i.Name] {
+			sb.WriteString(line)
+		}
+	})
+}
+
+// natOnlyInterfaces перечисляет интерфейсы, которые упоминаются только в правилах
+// NAT и отсутствуют в списках интерфейсов обеих конфигураций.
+func natOnlyInterfaces(running, desired *model.Config) []string {
+	oldIfaces, newIfaces := interfacesByName(running), interfacesByName(desired)
+	var names []string
+	for _, cfg := range []*model.Config{desired, running} {
+		natIfaces, _, _ := huaweiNATInterfaces(cfg, huaweiNATPoolIDs(cfg))
+		for _, name := range natIfaces {
+			_, inOld := oldIfaces[name]
+			_, inNew := newIfaces[name]
+			if !inOld && !inNew && !containsString(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// lineChanges сравнивает построчно два фрагмента конфигурации и возвращает строки
+// без отступов: removed — которых нет в newText, в обратном порядке, как их
+// нужно отменять; added — которых нет в oldText. Комментарии пропускаются.
+func lineChanges(oldText, newText, comment string) (removed, added []string) {
+	oldLines, newLines := configLines(oldText, comment), configLines(newText, comment)
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		if !containsString(newLines, oldLines[i]) {
+			removed = append(removed, oldLines[i])
+		}
+	}
+	for _, line := range newLines {
+		if !containsString(oldLines, line) {
+			added = append(added, line)
+		}
+	}
+	return removed, added
+}
+
+func configLines(text, comment string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, comment) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// writeGlobalLineDelta сравнивает однострочные глобальные команды (пулы и правила NAT).
+// Команды с одинаковыми первыми keyWords словами считаются одним объектом: при
+// remove=false такой объект пересоздаётся вместе с добавлением новых команд, при
+// remove=true отменяются команды, которых в новом тексте нет совсем. keyWords=0 —
+// ключом служит вся строка.
+func writeGlobalLineDelta(sb *strings.Builder, oldText, newText, comment string, keyWords int, undo func(string) string, remove bool) {
+	key := func(line string) string {
+		fields := strings.Fields(line)
+		if keyWords > 0 && keyWords < len(fields) {
+			fields = fields[:keyWords]
+		}
+		return strings.Join(fields, " ")
+	}
+	removed, added := lineChanges(oldText, newText, comment)
+	replaced := make(map[string]bool)
+	for _, line := range added {
+		replaced[key(line)] = true
+	}
+	if remove {
+		for _, line := range removed {
+			if !replaced[key(line)] {
+				sb.WriteString(undo(line) + "\n")
+			}
+		}
+		return
+	}
+	for _, line := range added {
+		for _, old := range removed {
+			if key(old) == key(line) {
+				sb.WriteString(undo(old) + "\n")
+			}
+		}
+		sb.WriteString(line + "\n")
+	}
+}
+
+// deltaSection — раздел конфигурации, который дельта не сравнивает: если его текст
+// в running и desired различается, об этом нужно предупредить.
+type deltaSection struct {
+	name  string
+	write func(sb *strings.Builder, cfg *model.Config)
+}
+
+var ciscoDeltaSections = []deltaSection{
+	{"DHCP pools and excluded addresses", writeCiscoDHCP},
+	{"time-ranges", writeCiscoTimeRanges},
+	{"object-groups", writeCiscoObjectGroups},
+	{"ACL descriptions", writeACLDescriptions},
+	{"prefix-lists and route-maps", writeCiscoPolicies},
+	{"routing process settings", func(sb *strings.Builder, cfg *model.Config) {
+		writeCiscoRouting(sb, withoutOSPFNetworks(cfg))
+		writeCiscoPassiveInterfaces(sb, cfg)
+	}},
+	{"VRF static routes", writeCiscoVRFRoutes},
+	{"lines", writeCiscoLines},
+	{"global services", writeCiscoServices},
+}
+
+var huaweiDeltaSections = []deltaSection{
+	{"DHCP pools and excluded addresses", writeHuaweiDHCP},
+	{"time-ranges", writeHuaweiTimeRanges},
+	{"object-groups", writeHuaweiObjectGroups},
+	{"ACL descriptions", writeACLDescriptions},
+	{"ACL remarks", writeACLRemarks},
+	{"prefix-lists and route-maps", writeHuaweiPolicies},
+	{"routing process settings", func(sb *strings.Builder, cfg *model.Config) {
+		writeHuaweiRouting(sb, withoutOSPFNetworks(cfg))
+		writeHuaweiSilentInterfaces(sb, cfg)
+	}},
+	{"VRF static routes", writeHuaweiVRFRoutes},
+	{"lines", writeHuaweiLines},
+	{"global services", writeHuaweiServices},
+}
+
+// changedSections возвращает названия разделов, текст которых в running и desired различается.
+func changedSections(sections []deltaSection, running, desired *model.Config) []string {
+	var changed []string
+	for _, s := range sections {
+		oldText := renderBlock(func(sb *strings.Builder) { s.write(sb, running) })
+		newText := renderBlock(func(sb *strings.Builder) { s.write(sb, desired) })
+		if oldText != newText {
+			changed = append(changed, s.name)
+		}
+	}
+	return changed
+}
+
+// withoutOSPFNetworks возвращает копию cfg без сетей OSPF: их дельта сравнивает отдельно.
+// Области без собственных настроек тоже убираются — Huawei задаёт их только ради сетей.
+func withoutOSPFNetworks(cfg *model.Config) *model.Config {
+	c := *cfg
+	c.OSPFProcesses = make([]model.OSPFProcess, len(cfg.OSPFProcesses))
+	for i, p := range cfg.OSPFProcesses {
+		p.Networks = nil
+		var areas []model.OSPFArea
+		for _, a := range p.Areas {
+			if a.Type != "" || a.NSSADefaultOriginate || a.DefaultCost != 0 || len(a.Ranges) > 0 {
+				areas = append(areas, a)
+			}
+		}
+		p.Areas = areas
+		c.OSPFProcesses[i] = p
+	}
+	return &c
+}
+
+func writeACLDescriptions(sb *strings.Builder, cfg *model.Config) {
+	for _, acl := range cfg.ACLs {
+		if acl.Description != "" {
+			sb.WriteString(fmt.Sprintf("%s %s\n", acl.Ref(), acl.Description))
+		}
+	}
+}
+
+// writeACLRemarks перечисляет примечания: в записях ACL Huawei они не сравниваются.
+func writeACLRemarks(sb *strings.Builder, cfg *model.Config) {
+	for _, acl := range cfg.ACLs {
+		for _, rule := range acl.Rules {
+			if rule.Action == "remark" {
+				sb.WriteString(fmt.Sprintf("%s %s\n", acl.Ref(), rule.Remark))
+			}
+		}
+	}
+}
+
+// writeCiscoACLDelta при remove=false создаёт новые списки и записи и заменяет
+// изменённые записи (по порядковому номеру), при remove=true удаляет лишние
+// записи и списки, которых нет в desired.
+func writeCiscoACLDelta(sb *strings.Builder, running, desired *model.Config, remove bool) {
+	from, to := running, desired
+	if remove {
+		from, to = desired, running
+	}
+	for _, acl := range to.ACLs {
+		ref := ciscoACLRef(to, acl.Ref())
+		old, exists := findACLByRef(from, acl.Ref())
+		if remove && !exists {
+			if acl.Name == "" {
+				sb.WriteString(fmt.Sprintf("no access-list %s\n", ref))
+			} else {
+				sb.WriteString(fmt.Sprintf("no ip access-list %s %s\n", ciscoACLKind(acl), ref))
+			}
+			continue
+		}
+		entries := ciscoACLEntries(acl)
+		oldEntries := ciscoACLEntries(old)
+		var lines []string
+		for _, seq := range sortedSeqs(entries) {
+			text, had := oldEntries[seq]
+			switch {
+			case remove && !had:
+				lines = append(lines, fmt.Sprintf(" no %d", seq))
+			case remove || text == entries[seq]:
+			case had:
+				lines = append(lines, fmt.Sprintf(" no %d", seq), fmt.Sprintf(" %d %s", seq, entries[seq]))
+			default:
+				lines = append(lines, fmt.Sprintf(" %d %s", seq, entries[seq]))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("ip access-list %s %s\n", ciscoACLKind(acl), ref))
+		sb.WriteString(strings.Join(lines, "\n") + "\n")
+		sb.WriteString(" exit\n")
+	}
+}
+
+func writeHuaweiACLDelta(sb *strings.Builder, running, desired *model.Config, remove bool) {
+	from, to := running, desired
+	if remove {
+		from, to = desired, running
+	}
+	for _, acl := range to.ACLs {
+		old, exists := findACLByRef(from, acl.Ref())
+		if remove && !exists {
+			if acl.Name != "" {
+				sb.WriteString(fmt.Sprintf("undo acl name %s\n", acl.Name))
+			} else {
+				sb.WriteString(fmt.Sprintf("undo acl number %d\n", acl.ID))
+			}
+			continue
+		}
+		entries := huaweiACLEntries(acl)
+		oldEntries := huaweiACLEntries(old)
+		var lines []string
+		for _, id := range sortedSeqs(entries) {
+			text, had := oldEntries[id]
+			switch {
+			case remove && !had:
+				lines = append(lines, fmt.Sprintf(" undo rule %d", id))
+			case remove || text == entries[id]:
+			case had:
+				lines = append(lines, fmt.Sprintf(" undo rule %d", id), fmt.Sprintf(" rule %d %s", id, entries[id]))
+			default:
+				lines = append(lines, fmt.Sprintf(" rule %d %s", id, entries[id]))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if exists && acl.Name != "" {
+			sb.WriteString(fmt.Sprintf("acl name %s\n", acl.Name))
+		} else {
+			sb.WriteString(formatHuaweiACLHeader(acl))
+		}
+		sb.WriteString(strings.Join(lines, "\n") + "\n")
+		sb.WriteString("quit\n")
+	}
+}
+
+// ciscoACLEntries сопоставляет порядковому номеру текст записи, как в "ip access-list".
+func ciscoACLEntries(acl model.ACL) map[int]string {
+	entries := make(map[int]string)
+	for i, seq := range ciscoRuleIDs(acl) {
+		entries[seq] = formatCiscoACLRule(acl.Rules[i], acl.Type)
+	}
+	return entries
+}
+
+// huaweiACLEntries сопоставляет номеру правила его текст; примечания не сравниваются.
+func huaweiACLEntries(acl model.ACL) map[int]string {
+	entries := make(map[int]string)
+	for i, id := range huaweiRuleIDs(acl) {
+		if id == 0 {
+			continue
+		}
+		rule := acl.Rules[i]
+		action := rule.Action
+		if action == "" {
+			action = "permit"
+		}
+		entries[id] = action + " " + formatHuaweiACLRule(rule, acl.Type)
+	}
+	return entries
+}
+
+// writeCiscoOSPFNetworkDelta выводит сети OSPF из to, которых нет в from;
+// с перевёрнутыми аргументами — команды их удаления.
+func writeCiscoOSPFNetworkDelta(sb *strings.Builder, from, to *model.Config, remove bool) {
+	for _, p := range to.OSPFProcesses {
+		missing := missingOSPFNetworks(from, p)
+		if len(missing) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("router ospf %d\n", p.ProcessID))
+		for _, n := range missing {
+			prefix := " "
+			if remove {
+				prefix = " no "
+			}
+			sb.WriteString(fmt.Sprintf("%snetwork %s %s area %s\n", prefix, n.Network, n.Wildcard, n.Area))
+		}
+		sb.WriteString(" exit\n")
+	}
+}
+
+func writeHuaweiOSPFNetworkDelta(sb *strings.Builder, from, to *model.Config, remove bool) {
+	for _, p := range to.OSPFProcesses {
+		missing := missingOSPFNetworks(from, p)
+		if len(missing) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("ospf %d\n", p.ProcessID))
+		byArea := make(map[string][]model.OSPFNetwork)
+		var areas []string
+		for _, n := range missing {
+			if _, ok := byArea[n.Area]; !ok {
+				areas = append(areas, n.Area)
+			}
+			byArea[n.Area] = append(byArea[n.Area], n)
+		}
+		for _, area := range areas {
+			sb.WriteString(fmt.Sprintf(" area %s\n", area))
+			for _, n := range byArea[area] {
+				prefix := "  "
+				if remove {
+					prefix = "  undo "
+				}
+				sb.WriteString(fmt.Sprintf("%snetwork %s %s\n", prefix, n.Network, n.Wildcard))
+			}
+			sb.WriteString(" quit\n")
+		}
+		sb.WriteString("quit\n")
+	}
+}
+
+// missingOSPFNetworks возвращает сети процесса p, которых нет в том же процессе cfg.
+func missingOSPFNetworks(cfg *model.Config, p model.OSPFProcess) []model.OSPFNetwork {
+	have := make(map[model.OSPFNetwork]bool)
+	for _, other := range cfg.OSPFProcesses {
+		if other.ProcessID == p.ProcessID {
+			for _, n := range other.Networks {
+				have[n] = true
+			}
+		}
+	}
+	var missing []model.OSPFNetwork
+	for _, n := range p.Networks {
+		if !have[n] {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
+
+func vlansByID(cfg *model.Config) map[int]model.Vlan {
+	vlans := make(map[int]model.Vlan)
+	for _, v := range cfg.Vlans {
+		vlans[v.ID] = v
+	}
+	return vlans
+}
+
+func interfacesByName(cfg *model.Config) map[string]model.Interface {
+	ifaces := make(map[string]model.Interface)
+	for _, i := range cfg.Interfaces {
+		ifaces[i.Name] = i
+	}
+	return ifaces
+}
+
+// isLogicalInterface отличает интерфейсы, которые можно удалить целиком, от
+// физических портов, у которых при удалении из конфигурации сбрасываются настройки.
+func isLogicalInterface(name string) bool {
+	if strings.Contains(name, ".") {
+		return true
+	}
+	for _, prefix := range []string{"Vlan", "Loopback", "LoopBack", "Tunnel", "Port-channel", "Eth-Trunk"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func routeLines(cfg *model.Config, format func(model.Route) string) []string {
+	var lines []string
+	for _, r := range cfg.Routes {
+		lines = append(lines, format(r))
+	}
+	return lines
+}
+
+// lastLine отбрасывает строки-комментарии, которые форматтер маршрута ставит перед командой.
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	return lines[len(lines)-1]
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// findACLByRef ищет список с тем же ключом ACL.Ref(); FindACL здесь не подходит,
+// так как по номеру он находит и именованные списки.
+func findACLByRef(cfg *model.Config, ref string) (model.ACL, bool) {
+	for _, acl := range cfg.ACLs {
+		if acl.Ref() == ref {
+			return acl, true
+		}
+	}
+	return model.ACL{}, false
+}
+
+func sortedSeqs(entries map[int]string) []int {
+	seqs := make([]int, 0, len(entries))
+	for seq := range entries {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+	return seqs
+}
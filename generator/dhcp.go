@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"converter/model"
+)
+
+func findDHCPRelayServers(cfg *model.Config, iface string) []string {
+	for _, relay := range cfg.DHCP.Relays {
+		if relay.Interface == iface {
+			return relay.Servers
+		}
+	}
+	return nil
+}
+
+func findInterfacePool(cfg *model.Config, iface string) (model.DHCPPool, bool) {
+	for _, pool := range cfg.DHCP.Pools {
+		if pool.Interface != "" && pool.Interface == iface {
+			return pool, true
+		}
+	}
+	return model.DHCPPool{}, false
+}
+
+// excludedInPool returns the excluded ranges that fall into the pool network.
+// Cisco keeps exclusions global while Huawei configures them per pool.
+func excludedInPool(excluded []model.DHCPExcluded, pool model.DHCPPool) []model.DHCPExcluded {
+	var result []model.DHCPExcluded
+	for _, ex := range excluded {
+		if pool.Network != "" && model.SubnetContains(pool.Network, pool.Mask, ex.Start) {
+			result = append(result, ex)
+		}
+	}
+	return result
+}
+
+func formatDHCPExcluded(ex model.DHCPExcluded) string {
+	if ex.End == "" || ex.End == ex.Start {
+		return ex.Start
+	}
+	return ex.Start + " " + ex.End
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"converter/model"
@@ -11,8 +12,18 @@ func GenerateHuawei(cfg *model.Config) string {
 	var sb strings.Builder
 	sb.WriteString("system-view\n")
 
+	var blocks []configBlock
+	add := func(key string, deps []string, write func(sb *strings.Builder)) {
+		if text := renderBlock(write); text != "" {
+			blocks = append(blocks, configBlock{key: key, deps: deps, text: text})
+		}
+	}
+
 	// vlan batch
-	if len(cfg.Vlans) > 0 {
+	add("vlans", nil, func(sb *strings.Builder) {
+		if len(cfg.Vlans) == 0 {
+			return
+		}
 		sb.WriteString("vlan batch")
 		for _, v := range cfg.Vlans {
 			sb.WriteString(fmt.Sprintf(" %d", v.ID))
@@ -27,158 +38,719 @@ func GenerateHuawei(cfg *model.Config) string {
 			}
 			sb.WriteString("quit\n\n")
 		}
-	}
+	})
+
+	// DHCP
+	add("dhcp", nil, func(sb *strings.Builder) { writeHuaweiDHCP(sb, cfg) })
 
-	// OSPF
-	ospfByProcessArea := make(map[int]map[string][]model.OSPF)
-	var processOrder []int
-	areaOrderByProcess := make(map[int][]string)
-	for _, o := range cfg.OSPF {
-		if _, ok := ospfByProcessArea[o.ProcessID]; !ok {
-			ospfByProcessArea[o.ProcessID] = make(map[string][]model.OSPF)
-			processOrder = append(processOrder, o.ProcessID)
+	// ACL и всё, на что они ссылаются, создаются до фильтров, политик и NAT
+	add("time-ranges", nil, func(sb *strings.Builder) { writeHuaweiTimeRanges(sb, cfg) })
+	add("object-groups", nil, func(sb *strings.Builder) { writeHuaweiObjectGroups(sb, cfg) })
+	add("acls", []string{"time-ranges", "object-groups"}, func(sb *strings.Builder) { writeHuaweiACLs(sb, cfg) })
+	add("policies", []string{"acls"}, func(sb *strings.Builder) { writeHuaweiPolicies(sb, cfg) })
+	poolIDs := huaweiNATPoolIDs(cfg)
+	natIfaces, natLines, natComments := huaweiNATInterfaces(cfg, poolIDs)
+	add("nat-pools", nil, func(sb *strings.Builder) {
+		writeHuaweiNATPools(sb, cfg)
+		for _, c := range natComments {
+			sb.WriteString(c)
 		}
-		if _, ok := ospfByProcessArea[o.ProcessID][o.Area]; !ok {
-			areaOrderByProcess[o.ProcessID] = append(areaOrderByProcess[o.ProcessID], o.Area)
+	})
+
+	// OSPF, RIP; EIGRP в Huawei отсутствует
+	add("routing", []string{"policies"}, func(sb *strings.Builder) { writeHuaweiRouting(sb, cfg) })
+	add("passive", passiveDeps(cfg), func(sb *strings.Builder) { writeHuaweiSilentInterfaces(sb, cfg) })
+
+	// Интерфейсы; NAT внешнего интерфейса пишется в его же блок
+	natDeps := []string{"acls", "nat-pools"}
+	for _, i := range cfg.Interfaces {
+		deps := append(interfaceDeps(i), "dhcp")
+		if len(natLines[i.Name]) > 0 {
+			deps = append(deps, natDeps...)
 		}
-		ospfByProcessArea[o.ProcessID][o.Area] = append(ospfByProcessArea[o.ProcessID][o.Area], o)
+		add(interfaceKey(i.Name), deps, func(sb *strings.Builder) { writeHuaweiInterface(sb, cfg, i, natLines[i.Name]) })
 	}
-	for _, pid := range processOrder {
-		sb.WriteString(fmt.Sprintf("ospf %d\n", pid))
-		if cfg.OSPFRouterID != "" {
-			sb.WriteString(fmt.Sprintf(" router-id %s\n", cfg.OSPFRouterID))
+	ifaces := interfacesByName(cfg)
+	for _, iface := range natIfaces {
+		if _, ok := ifaces[iface]; ok {
+			continue
 		}
-		if cfg.OSPFPassiveDefault {
-			sb.WriteString(" silent-interface all\n")
-			for _, iface := range cfg.OSPFNoPassiveIfaces {
-				sb.WriteString(fmt.Sprintf(" undo silent-interface %s\n", toHuaweiOspfIface(iface)))
+		add(interfaceKey(iface), natDeps, func(sb *strings.Builder) {
+			sb.WriteString(fmt.Sprintf("interface %s\n", toHuaweiIfaceName(iface)))
+			for _, line := range natLines[iface] {
+				sb.WriteString(line)
 			}
+			sb.WriteString("quit\n\n")
+		})
+	}
+
+	// Статические маршруты
+	add("routes", routeDeps(cfg), func(sb *strings.Builder) {
+		for _, r := range cfg.Routes {
+			sb.WriteString(formatHuaweiRoute(r))
 		}
-		for _, area := range areaOrderByProcess[pid] {
-			sb.WriteString(fmt.Sprintf(" area %s\n", area))
-			for _, o := range ospfByProcessArea[pid][area] {
-				sb.WriteString(fmt.Sprintf("  network %s %s\n", o.Network, o.Wildcard))
-			}
+		writeHuaweiVRFRoutes(sb, cfg)
+	})
+	add("lines", []string{"acls"}, func(sb *strings.Builder) { writeHuaweiLines(sb, cfg) })
+	add("services", nil, func(sb *strings.Builder) { writeHuaweiServices(sb, cfg) })
+
+	writeBlocks(&sb, blocks, "#")
+	sb.WriteString("return\n")
+
+	return sb.String()
+}
+
+func writeHuaweiNATPools(sb *strings.Builder, cfg *model.Config) {
+	poolIDs := huaweiNATPoolIDs(cfg)
+	for _, pool := range cfg.NATPool {
+		sb.WriteString(fmt.Sprintf("nat address-group %d %s %s\n", poolIDs[pool.Name], pool.Start, pool.End))
+	}
+}
+
+func writeHuaweiDHCP(sb *strings.Builder, cfg *model.Config) {
+	if cfg.DHCP.Empty() {
+		return
+	}
+	sb.WriteString("dhcp enable\n\n")
+	for _, pool := range cfg.DHCP.Pools {
+		if pool.Interface != "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("ip pool %s\n", pool.Name))
+		if len(pool.DefaultRouters) > 0 {
+			sb.WriteString(fmt.Sprintf(" gateway-list %s\n", strings.Join(pool.DefaultRouters, " ")))
+		}
+		if pool.Network != "" {
+			sb.WriteString(fmt.Sprintf(" network %s mask %s\n", pool.Network, model.NormalizeMask(pool.Mask)))
+		}
+		for _, ex := range excludedInPool(cfg.DHCP.Excluded, pool) {
+			sb.WriteString(fmt.Sprintf(" excluded-ip-address %s\n", formatDHCPExcluded(ex)))
 		}
+		writeHuaweiDHCPOptions(sb, "", pool)
 		sb.WriteString("quit\n\n")
 	}
+}
 
-	// Интерфейсы
-	for _, i := range cfg.Interfaces {
-		// L3 interface → Vlanif
-		nameLower := strings.ToLower(i.Name)
-		if strings.HasPrefix(nameLower, "vlan") || strings.HasPrefix(nameLower, "vlanif") {
-			id := strings.TrimLeftFunc(i.Name, func(r rune) bool { return r < '0' || r > '9' })
-			sb.WriteString(fmt.Sprintf("interface Vlanif %s\n", id))
-		} else {
-			sb.WriteString(fmt.Sprintf("interface %s\n", i.Name))
-		}
+func writeHuaweiRouting(sb *strings.Builder, cfg *model.Config) {
+	for _, p := range cfg.OSPFProcesses {
+		writeHuaweiOSPF(sb, p)
+	}
+	for _, p := range cfg.RIP {
+		writeHuaweiRIP(sb, p)
+	}
+	for _, p := range cfg.ISIS {
+		writeHuaweiISIS(sb, cfg, p)
+	}
+	for _, p := range cfg.EIGRP {
+		sb.WriteString(fmt.Sprintf("# EIGRP AS %d not converted: not supported on Huawei\n", p.AS))
+	}
+}
 
-		if i.Description != "" {
-			sb.WriteString(fmt.Sprintf(" description %s\n", i.Description))
+func writeHuaweiLines(sb *strings.Builder, cfg *model.Config) {
+	for _, l := range cfg.Lines {
+		sb.WriteString(fmt.Sprintf("user-interface %s\n", formatLineRange(l)))
+		if l.ACLIn != "" {
+			sb.WriteString(fmt.Sprintf(" acl %s inbound\n", huaweiACLRef(cfg, l.ACLIn)))
 		}
+		if l.ACLOut != "" {
+			sb.WriteString(fmt.Sprintf(" acl %s outbound\n", huaweiACLRef(cfg, l.ACLOut)))
+		}
+		sb.WriteString("quit\n\n")
+	}
+}
+
+func writeHuaweiServices(sb *strings.Builder, cfg *model.Config) {
+	if cfg.STP.Mode != "" {
+		sb.WriteString(fmt.Sprintf("stp mode %s\n", mapCiscoSTPToHuawei(cfg.STP.Mode)))
+	}
+	if cfg.Service.SMTP {
+		sb.WriteString("smtp server enable\n")
+	}
+	if cfg.Service.FTP {
+		sb.WriteString("ftp server enable\n")
+	}
+}
+
+// writeHuaweiInterface пишет блок интерфейса; extra — дополнительные строки
+// (NAT внешнего интерфейса), которые должны попасть в тот же блок.
+func writeHuaweiInterface(sb *strings.Builder, cfg *model.Config, i model.Interface, extra []string) {
+	// L3 interface → Vlanif
+	nameLower := strings.ToLower(i.Name)
+	if strings.HasPrefix(nameLower, "vlan") || strings.HasPrefix(nameLower, "vlanif") {
+		id := strings.TrimLeftFunc(i.Name, func(r rune) bool { return r < '0' || r > '9' })
+		sb.WriteString(fmt.Sprintf("interface Vlanif %s\n", id))
+	} else {
+		sb.WriteString(fmt.Sprintf("interface %s\n", i.Name))
+	}
+
+	if i.Description != "" {
+		sb.WriteString(fmt.Sprintf(" description %s\n", i.Description))
+	}
+
+	// Access
+	if i.Vlan != 0 && isHuaweiSubinterface(i.Name) {
+		sb.WriteString(fmt.Sprintf(" vlan-type dot1q %d\n", i.Vlan))
+	} else if i.Vlan != 0 {
+		sb.WriteString(" port link-type access\n")
+		sb.WriteString(fmt.Sprintf(" port default vlan %d\n", i.Vlan))
+	}
+
+	// Trunk
+	if i.TrunkVlans != "" {
+		sb.WriteString(" port link-type trunk\n")
+		sb.WriteString(fmt.Sprintf(" port trunk allow-pass vlan %s\n", i.TrunkVlans))
+	}
 
-		// Access
-		if i.Vlan != 0 && isHuaweiSubinterface(i.Name) {
-			sb.WriteString(fmt.Sprintf(" vlan-type dot1q %d\n", i.Vlan))
-		} else if i.Vlan != 0 {
-			sb.WriteString(" port link-type access\n")
-			sb.WriteString(fmt.Sprintf(" port default vlan %d\n", i.Vlan))
+	if i.Bandwidth != 0 {
+		sb.WriteString(fmt.Sprintf(" bandwidth %d\n", max(1, i.Bandwidth/1000)))
+	}
+
+	// IP
+	if i.IP != "" {
+		sb.WriteString(fmt.Sprintf(" ip address %s\n", i.IP))
+	}
+
+	writeHuaweiInterfaceServices(sb, cfg, i)
+	if i.ACLIn != "" {
+		sb.WriteString(fmt.Sprintf(" traffic-filter inbound acl %s\n", huaweiACLRef(cfg, i.ACLIn)))
+	}
+	if i.ACLOut != "" {
+		sb.WriteString(fmt.Sprintf(" traffic-filter outbound acl %s\n", huaweiACLRef(cfg, i.ACLOut)))
+	}
+	for _, line := range extra {
+		sb.WriteString(line)
+	}
+
+	sb.WriteString("quit\n\n")
+}
+
+// writeHuaweiInterfaceServices пишет DHCP и настройки протоколов маршрутизации интерфейса.
+func writeHuaweiInterfaceServices(sb *strings.Builder, cfg *model.Config, i model.Interface) {
+	writeHuaweiInterfaceDHCP(sb, cfg, i)
+	if i.OSPF != nil {
+		writeHuaweiInterfaceOSPF(sb, *i.OSPF)
+	}
+	writeHuaweiInterfaceISIS(sb, cfg, i)
+}
+
+func writeHuaweiInterfaceOSPF(sb *strings.Builder, o model.InterfaceOSPF) {
+	if o.ProcessID != 0 && o.Area != "" {
+		sb.WriteString(fmt.Sprintf(" ospf enable %d area %s\n", o.ProcessID, o.Area))
+	}
+	if o.NetworkType != "" {
+		sb.WriteString(fmt.Sprintf(" ospf network-type %s\n", toHuaweiOSPFNetworkType(o.NetworkType)))
+	}
+	if o.Cost != 0 {
+		sb.WriteString(fmt.Sprintf(" ospf cost %d\n", o.Cost))
+	}
+	if o.Priority != nil {
+		sb.WriteString(fmt.Sprintf(" ospf dr-priority %d\n", *o.Priority))
+	}
+	if o.HelloInterval != 0 {
+		sb.WriteString(fmt.Sprintf(" ospf timer hello %d\n", o.HelloInterval))
+	}
+	if o.DeadInterval != 0 {
+		sb.WriteString(fmt.Sprintf(" ospf timer dead %d\n", o.DeadInterval))
+	}
+	// у Huawei ключ задаётся в той же команде, что и режим: без ключа её не ввести
+	keyMissing := (o.AuthMode == "md5" || o.AuthMode == "simple") && (o.AuthKey == "" || o.AuthKeyEncrypted)
+	switch {
+	case keyMissing && o.AuthKeyEncrypted:
+		sb.WriteString(fmt.Sprintf(" # OSPF %s key is stored encrypted on the source device, set authentication manually\n", o.AuthMode))
+	case keyMissing:
+		sb.WriteString(fmt.Sprintf(" # OSPF %s authentication has no key, set it manually\n", o.AuthMode))
+	case o.AuthMode == "md5":
+		sb.WriteString(fmt.Sprintf(" ospf authentication-mode md5 %d cipher %s\n", ospfKeyID(o), o.AuthKey))
+	case o.AuthMode == "simple":
+		sb.WriteString(fmt.Sprintf(" ospf authentication-mode simple cipher %s\n", o.AuthKey))
+	case o.AuthMode == "key-chain":
+		sb.WriteString(fmt.Sprintf(" ospf authentication-mode keychain %s\n", o.KeyChain))
+	}
+}
+
+func writeHuaweiISIS(sb *strings.Builder, cfg *model.Config, p model.ISISProcess) {
+	sb.WriteString(fmt.Sprintf("isis %d\n", huaweiISISProcessID(cfg, p.Tag)))
+	if p.Level != "" {
+		sb.WriteString(fmt.Sprintf(" is-level %s\n", p.Level))
+	}
+	if p.MetricStyle != "" {
+		sb.WriteString(fmt.Sprintf(" cost-style %s\n", p.MetricStyle))
+	}
+	for _, net := range p.NET {
+		sb.WriteString(fmt.Sprintf(" network-entity %s\n", net))
+	}
+	if p.DefaultInformation != nil {
+		line := " default-route-advertise"
+		if p.DefaultInformation.Always {
+			line += " always"
 		}
+		if p.DefaultInformation.RouteMap != "" {
+			line += " route-policy " + p.DefaultInformation.RouteMap
+		}
+		sb.WriteString(line + "\n")
+	}
+	for _, r := range p.Redistribute {
+		sb.WriteString(formatHuaweiImportRoute(r, false))
+	}
+	sb.WriteString("quit\n\n")
+}
 
-		// Trunk
-		if i.TrunkVlans != "" {
-			sb.WriteString(" port link-type trunk\n")
-			sb.WriteString(fmt.Sprintf(" port trunk allow-pass vlan %s\n", i.TrunkVlans))
+// writeHuaweiInterfaceISIS пишет "isis enable"; пассивный интерфейс Cisco становится "isis silent".
+func writeHuaweiInterfaceISIS(sb *strings.Builder, cfg *model.Config, iface model.Interface) {
+	o := iface.ISIS
+	silentTag, silent := isisPassiveTag(cfg, iface.Name)
+	if o == nil && !silent {
+		return
+	}
+	if o == nil {
+		o = &model.InterfaceISIS{Tag: silentTag}
+	}
+	sb.WriteString(fmt.Sprintf(" isis enable %d\n", huaweiISISProcessID(cfg, o.Tag)))
+	if o.CircuitType != "" {
+		sb.WriteString(fmt.Sprintf(" isis circuit-level %s\n", o.CircuitType))
+	}
+	if o.Metric != 0 {
+		sb.WriteString(fmt.Sprintf(" isis cost %d\n", o.Metric))
+	}
+	if o.PointToPoint {
+		sb.WriteString(" isis circuit-type p2p\n")
+	}
+	if silent {
+		sb.WriteString(" isis silent\n")
+	}
+}
+
+func isisPassiveTag(cfg *model.Config, name string) (string, bool) {
+	for _, p := range cfg.ISIS {
+		for _, iface := range p.PassiveInterfaces {
+			if iface == name {
+				return p.Tag, true
+			}
 		}
+	}
+	return "", false
+}
 
-		// IP
-		if i.IP != "" {
-			sb.WriteString(fmt.Sprintf(" ip address %s\n", i.IP))
+// huaweiISISProcessID переводит тег IS-IS Cisco в номер процесса Huawei:
+// числовой тег сохраняет свой номер, остальные процессы по порядку получают
+// наименьшие номера, не занятые другими процессами.
+func huaweiISISProcessID(cfg *model.Config, tag string) int {
+	used := make(map[int]bool)
+	for _, p := range cfg.ISIS {
+		if id, ok := numericISISTag(p.Tag); ok {
+			used[id] = true
+		}
+	}
+	if id, ok := numericISISTag(tag); ok {
+		return id
+	}
+	next := 1
+	for _, p := range cfg.ISIS {
+		if _, ok := numericISISTag(p.Tag); ok {
+			continue
+		}
+		for used[next] {
+			next++
+		}
+		if p.Tag == tag {
+			return next
 		}
+		used[next] = true
+	}
+	return 1
+}
 
-		sb.WriteString("quit\n\n")
+func numericISISTag(tag string) (int, bool) {
+	id, err := strconv.Atoi(tag)
+	return id, err == nil && id > 0
+}
+
+func toHuaweiOSPFNetworkType(t string) string {
+	switch t {
+	case "point-to-point":
+		return "p2p"
+	case "point-to-multipoint":
+		return "p2mp"
+	case "non-broadcast":
+		return "nbma"
 	}
-	// Статические маршруты
-	for _, r := range cfg.Routes {
-		sb.WriteString(fmt.Sprintf("ip route-static %s %s %s\n", r.Destination, r.Mask, r.Gateway))
-	}
-	for _, acl := range cfg.ACLs {
-		huaweiACLID := mapACLIDToHuawei(acl.ID, acl.Type)
-		sb.WriteString(fmt.Sprintf("acl number %d\n", huaweiACLID))
-		seq := 5
-		for _, rule := range acl.Rules {
-			if rule.Raw != "" {
-				sb.WriteString(fmt.Sprintf(" # unsupported ACL rule: %s\n", rule.Raw))
-				continue
-			}
-			action := rule.Action
-			if action == "" {
-				action = "permit"
+	return t
+}
+
+func writeHuaweiOSPF(sb *strings.Builder, p model.OSPFProcess) {
+	if p.RouterID != "" {
+		sb.WriteString(fmt.Sprintf("ospf %d router-id %s\n", p.ProcessID, p.RouterID))
+	} else {
+		sb.WriteString(fmt.Sprintf("ospf %d\n", p.ProcessID))
+	}
+	if p.ReferenceBandwidth != 0 {
+		sb.WriteString(fmt.Sprintf(" bandwidth-reference %d\n", p.ReferenceBandwidth))
+	}
+	if p.SPFTimers != nil {
+		sb.WriteString(fmt.Sprintf(" spf-schedule-interval intelligent-timer %d %d %d\n", p.SPFTimers.Max, p.SPFTimers.Start, p.SPFTimers.Hold))
+	}
+	if d := p.DefaultInformation; d != nil {
+		line := " default-route-advertise"
+		if d.Always {
+			line += " always"
+		}
+		if d.Metric != 0 {
+			line += fmt.Sprintf(" cost %d", d.Metric)
+		}
+		if d.MetricType != 0 {
+			line += fmt.Sprintf(" type %d", d.MetricType)
+		}
+		if d.RouteMap != "" {
+			line += " route-policy " + d.RouteMap
+		}
+		sb.WriteString(line + "\n")
+	}
+	for _, r := range p.Redistribute {
+		sb.WriteString(formatHuaweiImportRoute(r, true))
+	}
+	for _, id := range p.AreaIDs() {
+		sb.WriteString(fmt.Sprintf(" area %s\n", id))
+		if a, ok := p.Area(id); ok {
+			switch a.Type {
+			case "stub":
+				sb.WriteString("  stub\n")
+			case "totally-stubby":
+				sb.WriteString("  stub no-summary\n")
+			case "nssa", "totally-nssa":
+				line := "  nssa"
+				if a.NSSADefaultOriginate {
+					line += " default-route-advertise"
+				}
+				if a.Type == "totally-nssa" {
+					line += " no-summary"
+				}
+				sb.WriteString(line + "\n")
 			}
-			ruleSeq := rule.Sequence
-			if ruleSeq == 0 {
-				ruleSeq = seq
+			if a.DefaultCost != 0 {
+				sb.WriteString(fmt.Sprintf("  default-cost %d\n", a.DefaultCost))
 			}
-			if isExtendedACLRule(rule, acl.Type) {
-				proto := rule.Protocol
-				if proto == "" {
-					proto = "ip"
+			for _, r := range a.Ranges {
+				line := fmt.Sprintf("  abr-summary %s %s", r.Network, model.NormalizeMask(r.Mask))
+				if r.NotAdvertise {
+					line += " not-advertise"
 				}
-				line := fmt.Sprintf(" rule %d %s %s source %s", ruleSeq, action, proto, formatHuaweiAddress(rule.Source, rule.Wildcard))
-				if rule.SrcPort != "" {
-					line += " source-port " + rule.SrcPort
-				}
-				line += " destination " + formatHuaweiAddress(rule.Destination, rule.DstWildcard)
-				if rule.DstPort != "" {
-					line += " destination-port " + rule.DstPort
+				if r.Cost != 0 {
+					line += fmt.Sprintf(" cost %d", r.Cost)
 				}
 				sb.WriteString(line + "\n")
-			} else {
-				sb.WriteString(fmt.Sprintf(" rule %d %s source %s\n", ruleSeq, action, formatHuaweiAddress(rule.Source, rule.Wildcard)))
 			}
-			seq += 5
 		}
+		for _, n := range p.Networks {
+			if n.Area == id {
+				sb.WriteString(fmt.Sprintf("  network %s %s\n", n.Network, n.Wildcard))
+			}
+		}
+		sb.WriteString(" quit\n")
+	}
+	sb.WriteString("quit\n\n")
+}
+
+// writeHuaweiRIP пишет процесс RIP; единственный процесс Cisco без номера становится "rip 1".
+func writeHuaweiRIP(sb *strings.Builder, p model.RIPProcess) {
+	pid := p.ProcessID
+	if pid == 0 {
+		pid = 1
+	}
+	sb.WriteString(fmt.Sprintf("rip %d\n", pid))
+	if p.Version != 0 {
+		sb.WriteString(fmt.Sprintf(" version %d\n", p.Version))
+	}
+	if p.NoAutoSummary {
+		sb.WriteString(" undo summary\n")
+	}
+	for _, n := range p.Networks {
+		sb.WriteString(fmt.Sprintf(" network %s\n", n))
+	}
+	if d := p.DefaultInformation; d != nil {
+		line := " default-route originate"
+		if d.Metric != 0 {
+			line += fmt.Sprintf(" cost %d", d.Metric)
+		}
+		if d.RouteMap != "" {
+			line += " route-policy " + d.RouteMap
+		}
+		sb.WriteString(line + "\n")
+	}
+	for _, r := range p.Redistribute {
+		sb.WriteString(formatHuaweiImportRoute(r, false))
+	}
+	sb.WriteString("quit\n\n")
+}
+
+// writeHuaweiSilentInterfaces повторно входит в процессы OSPF и RIP и задаёт
+// silent-interface после блоков интерфейсов, на которые команды ссылаются.
+// Для IS-IS silent задаётся в самом интерфейсе.
+func writeHuaweiSilentInterfaces(sb *strings.Builder, cfg *model.Config) {
+	section := func(header string, passiveDefault bool, passive, noPassive []string) {
+		if !passiveDefault && len(passive) == 0 {
+			return
+		}
+		sb.WriteString(header + "\n")
+		writeHuaweiSilent(sb, passiveDefault, passive, noPassive)
 		sb.WriteString("quit\n\n")
 	}
-	if len(cfg.NATRule) > 0 {
-		for _, r := range cfg.NATRule {
-			aclType := findACLTypeForHuawei(cfg, r.ACLID)
-			hwACL := mapACLIDToHuawei(r.ACLID, aclType)
-			sb.WriteString(fmt.Sprintf("interface %s\n", r.Outside))
-			sb.WriteString(fmt.Sprintf(" nat outbound %d\n", hwACL))
-			sb.WriteString("quit\n")
+	for _, p := range cfg.OSPFProcesses {
+		section(fmt.Sprintf("ospf %d", p.ProcessID), p.PassiveDefault, p.PassiveInterfaces, p.NoPassiveInterfaces)
+	}
+	for _, p := range cfg.RIP {
+		pid := p.ProcessID
+		if pid == 0 {
+			pid = 1
 		}
-	} else {
-		for _, n := range cfg.NAT {
-			sb.WriteString(fmt.Sprintf("nat address-group 1 %s %s\n", n.Inside, n.Outside))
+		section(fmt.Sprintf("rip %d", pid), p.PassiveDefault, p.PassiveInterfaces, p.NoPassiveInterfaces)
+	}
+}
+
+func writeHuaweiSilent(sb *strings.Builder, passiveDefault bool, passive, noPassive []string) {
+	if passiveDefault {
+		sb.WriteString(" silent-interface all\n")
+		for _, iface := range noPassive {
+			sb.WriteString(fmt.Sprintf(" undo silent-interface %s\n", toHuaweiIfaceName(iface)))
 		}
+		return
 	}
-	if cfg.STP.Mode != "" {
-		sb.WriteString(fmt.Sprintf("stp mode %s\n", mapCiscoSTPToHuawei(cfg.STP.Mode)))
+	for _, iface := range passive {
+		sb.WriteString(fmt.Sprintf(" silent-interface %s\n", toHuaweiIfaceName(iface)))
 	}
-	if cfg.Service.SMTP {
-		sb.WriteString("smtp server enable\n")
+}
+
+// formatHuaweiImportRoute формирует "import-route"; тип метрики есть только у OSPF.
+func formatHuaweiImportRoute(r model.Redistribution, withType bool) string {
+	proto := r.Protocol
+	if proto == "connected" {
+		proto = "direct"
 	}
-	if cfg.Service.FTP {
-		sb.WriteString("ftp server enable\n")
+	if proto == "eigrp" {
+		return fmt.Sprintf(" # import-route eigrp %s not supported\n", r.Process)
 	}
-	sb.WriteString("return\n")
+	line := " import-route " + proto
+	if r.Process != "" {
+		line += " " + r.Process
+	}
+	if r.Metric != 0 {
+		line += fmt.Sprintf(" cost %d", r.Metric)
+	}
+	if withType && r.MetricType != 0 {
+		line += fmt.Sprintf(" type %d", r.MetricType)
+	}
+	if r.Tag != 0 {
+		line += fmt.Sprintf(" tag %d", r.Tag)
+	}
+	if r.RouteMap != "" {
+		line += " route-policy " + r.RouteMap
+	}
+	return line + "\n"
+}
 
-	return sb.String()
+func writeHuaweiInterfaceDHCP(sb *strings.Builder, cfg *model.Config, iface model.Interface) {
+	if pool, ok := findInterfacePool(cfg, iface.Name); ok {
+		sb.WriteString(" dhcp select interface\n")
+		for _, ex := range excludedInPool(cfg.DHCP.Excluded, pool) {
+			sb.WriteString(fmt.Sprintf(" dhcp server excluded-ip-address %s\n", formatDHCPExcluded(ex)))
+		}
+		writeHuaweiDHCPOptions(sb, "dhcp server ", pool)
+		return
+	}
+	if servers := findDHCPRelayServers(cfg, iface.Name); len(servers) > 0 {
+		sb.WriteString(" dhcp select relay\n")
+		for _, server := range servers {
+			sb.WriteString(fmt.Sprintf(" dhcp relay server-ip %s\n", server))
+		}
+		return
+	}
+	if servesGlobalDHCPPool(cfg, iface) {
+		sb.WriteString(" dhcp select global\n")
+	}
+}
+
+func writeHuaweiDHCPOptions(sb *strings.Builder, prefix string, pool model.DHCPPool) {
+	if len(pool.DNSServers) > 0 {
+		sb.WriteString(fmt.Sprintf(" %sdns-list %s\n", prefix, strings.Join(pool.DNSServers, " ")))
+	}
+	if pool.DomainName != "" {
+		sb.WriteString(fmt.Sprintf(" %sdomain-name %s\n", prefix, pool.DomainName))
+	}
+	if pool.Lease != nil {
+		if pool.Lease.Infinite {
+			sb.WriteString(fmt.Sprintf(" %slease unlimited\n", prefix))
+		} else {
+			sb.WriteString(fmt.Sprintf(" %slease day %d hour %d minute %d\n", prefix, pool.Lease.Days, pool.Lease.Hours, pool.Lease.Minutes))
+		}
+	}
+}
+
+// servesGlobalDHCPPool определяет, раздает ли интерфейс адреса из глобального пула.
+func servesGlobalDHCPPool(cfg *model.Config, iface model.Interface) bool {
+	for _, name := range cfg.DHCP.GlobalInterfaces {
+		if name == iface.Name {
+			return true
+		}
+	}
+	addr, _ := model.SplitIPMask(iface.IP)
+	if addr == "" {
+		return false
+	}
+	for _, pool := range cfg.DHCP.Pools {
+		if pool.Interface == "" && pool.Network != "" && model.SubnetContains(pool.Network, pool.Mask, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeHuaweiVRFRoutes переносит маршруты VPN-инстансов строками исходной
+// конфигурации, если она тоже Huawei; строки Cisco остаются комментариями.
+func writeHuaweiVRFRoutes(sb *strings.Builder, cfg *model.Config) {
+	for _, line := range cfg.VRFRoutes {
+		if cfg.DeviceType == "huawei" {
+			sb.WriteString(line + "\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("# VRF route not converted: %s\n", line))
+		}
+	}
+}
+
+func formatHuaweiRoute(r model.Route) string {
+	line := fmt.Sprintf("ip route-static %s %s", r.Destination, model.NormalizeMask(r.Mask))
+	if r.Interface != "" {
+		line += " " + toHuaweiIfaceName(r.Interface)
+	}
+	if r.Gateway != "" {
+		line += " " + r.Gateway
+	}
+	if r.Distance != 0 {
+		line += fmt.Sprintf(" preference %d", r.Distance)
+	}
+	if r.Tag != 0 {
+		line += fmt.Sprintf(" tag %d", r.Tag)
+	}
+	if r.Permanent {
+		line += " permanent"
+	} else if r.Track != "" {
+		if strings.HasPrefix(r.Track, "bfd-session ") || strings.HasPrefix(r.Track, "nqa ") {
+			line += " track " + r.Track
+		} else {
+			line = fmt.Sprintf("# track %s not converted\n", r.Track) + line
+		}
+	}
+	if r.Name != "" {
+		line += " description " + r.Name
+	}
+	return line + "\n"
 }
 
-func mapACLIDToHuawei(id int, aclType string) int {
-	if aclType == "extended" && ((id >= 100 && id <= 199) || (id >= 2000 && id <= 2699)) {
-		if id >= 2000 {
-			return id + 1000
+// huaweiNATInterfaces раскладывает "nat outbound" и статический NAT по внешним
+// интерфейсам; правила, для которых интерфейс не найден, возвращаются комментариями.
+func huaweiNATInterfaces(cfg *model.Config, poolIDs map[string]int) ([]string, map[string][]string, []string) {
+	natLines := make(map[string][]string)
+	var natIfaces, comments []string
+	addNATLine := func(iface, line string) {
+		if _, ok := natLines[iface]; !ok {
+			natIfaces = append(natIfaces, iface)
 		}
-		return id + 2900
+		natLines[iface] = append(natLines[iface], line)
 	}
-	if (aclType == "standard" || aclType == "basic" || aclType == "") && id >= 1 && id <= 1999 {
-		return 2000 + id
+	for _, r := range cfg.NATRule {
+		hwACL := huaweiACLRef(cfg, r.ACLRef())
+		outside := r.Outside
+		if outside == "" {
+			outside = natOutsideInterface(cfg)
+		}
+		if outside == "" {
+			comments = append(comments, fmt.Sprintf("# nat outbound %s without outside interface\n", hwACL))
+			continue
+		}
+		line := fmt.Sprintf(" nat outbound %s", hwACL)
+		if r.Pool != "" {
+			line += fmt.Sprintf(" address-group %d", poolIDs[r.Pool])
+			if !r.Overload {
+				line += " no-pat"
+			}
+		}
+		addNATLine(outside, line+"\n")
+	}
+	for _, n := range cfg.StaticNAT {
+		iface := staticNATInterface(cfg, n)
+		if iface == "" {
+			comments = append(comments, fmt.Sprintf("# static NAT %s without outside interface\n", n.InsideAddress))
+			continue
+		}
+		addNATLine(iface, formatHuaweiStaticNAT(n, iface))
 	}
-	return id
+	return natIfaces, natLines, comments
+}
+
+// staticNATInterface выбирает внешний интерфейс для "nat server"/"nat static".
+func staticNATInterface(cfg *model.Config, n model.StaticNAT) string {
+	if n.Interface != "" {
+		return n.Interface
+	}
+	if n.GlobalInterface != "" {
+		return n.GlobalInterface
+	}
+	return natOutsideInterface(cfg)
+}
+
+// natOutsideInterface возвращает первый известный внешний интерфейс NAT.
+func natOutsideInterface(cfg *model.Config) string {
+	for _, i := range cfg.Interfaces {
+		if i.NAT == "outside" {
+			return i.Name
+		}
+	}
+	for _, r := range cfg.NATRule {
+		if r.Outside != "" {
+			return r.Outside
+		}
+	}
+	return ""
+}
+
+// huaweiNATPoolIDs назначает номера address-group: сохраняет известные и выдает свободные остальным.
+func huaweiNATPoolIDs(cfg *model.Config) map[string]int {
+	ids := make(map[string]int)
+	used := make(map[int]bool)
+	for _, pool := range cfg.NATPool {
+		if pool.ID != 0 && !used[pool.ID] {
+			ids[pool.Name] = pool.ID
+			used[pool.ID] = true
+		}
+	}
+	next := 1
+	for _, pool := range cfg.NATPool {
+		if _, ok := ids[pool.Name]; ok {
+			continue
+		}
+		for used[next] {
+			next++
+		}
+		ids[pool.Name] = next
+		used[next] = true
+	}
+	return ids
+}
+
+func formatHuaweiStaticNAT(n model.StaticNAT, iface string) string {
+	global := n.GlobalAddress
+	if n.GlobalInterface != "" {
+		if n.GlobalInterface == iface {
+			global = "current-interface"
+		} else {
+			global = "interface " + toHuaweiIfaceName(n.GlobalInterface)
+		}
+	}
+	if n.Protocol == "" {
+		if n.GlobalInterface != "" {
+			return fmt.Sprintf(" nat server global %s inside %s\n", global, n.InsideAddress)
+		}
+		return fmt.Sprintf(" nat static global %s inside %s\n", global, n.InsideAddress)
+	}
+	return fmt.Sprintf(" nat server protocol %s global %s %s inside %s %s\n", n.Protocol, global, n.GlobalPort, n.InsideAddress, n.InsidePort)
 }
 
 func formatHuaweiAddress(addr, wildcard string) string {
@@ -191,15 +763,6 @@ func formatHuaweiAddress(addr, wildcard string) string {
 	return addr + " " + wildcard
 }
 
-func findACLTypeForHuawei(cfg *model.Config, id int) string {
-	for _, acl := range cfg.ACLs {
-		if acl.ID == id {
-			return acl.Type
-		}
-	}
-	return ""
-}
-
 func mapCiscoSTPToHuawei(mode string) string {
 	l := strings.ToLower(strings.TrimSpace(mode))
 	switch l {
@@ -210,7 +773,7 @@ func mapCiscoSTPToHuawei(mode string) string {
 	}
 }
 
-func toHuaweiOspfIface(iface string) string {
+func toHuaweiIfaceName(iface string) string {
 	i := strings.TrimSpace(iface)
 	low := strings.ToLower(i)
 	if strings.HasPrefix(low, "vlanif") {
@@ -223,6 +786,9 @@ func toHuaweiOspfIface(iface string) string {
 			return "Vlanif" + id
 		}
 	}
+	if strings.HasPrefix(low, "null") {
+		return "NULL" + strings.TrimSpace(i[len("null"):])
+	}
 	return i
 }
 
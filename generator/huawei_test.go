@@ -0,0 +1,17 @@
+package generator
+
+import (
+	"testing"
+
+	"converter/model"
+)
+
+func TestHuaweiISISProcessIDAvoidsNumericTags(t *testing.T) {
+	cfg := &model.Config{ISIS: []model.ISISProcess{{Tag: "core"}, {Tag: "1"}, {Tag: "edge"}, {Tag: "3"}}}
+	want := map[string]int{"core": 2, "1": 1, "edge": 4, "3": 3}
+	for tag, id := range want {
+		if got := huaweiISISProcessID(cfg, tag); got != id {
+			t.Errorf("tag %q: got isis %d, want %d", tag, got, id)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"converter/model"
+)
+
+// configBlock — часть сгенерированной конфигурации, которая вводится целиком,
+// и ключи блоков, на которые её команды ссылаются.
+type configBlock struct {
+	key  string
+	deps []string
+	text string
+}
+
+// writeBlocks выводит блоки так, чтобы каждый шёл после всех блоков, от которых
+// зависит; при прочих равных сохраняется исходный порядок. Зависимости от
+// отсутствующих блоков пропускаются. Цикл разрывается первым невыведенным блоком,
+// а перед ним пишется комментарий (comment — его префикс) со списком блоков цикла.
+func writeBlocks(sb *strings.Builder, blocks []configBlock, comment string) {
+	pending := make(map[string]int)
+	for _, b := range blocks {
+		pending[b.key]++
+	}
+	ready := func(b configBlock) bool {
+		for _, dep := range b.deps {
+			if dep != b.key && pending[dep] > 0 {
+				return false
+			}
+		}
+		return true
+	}
+	done := make([]bool, len(blocks))
+	for range blocks {
+		next := -1
+		for i, b := range blocks {
+			if !done[i] && ready(b) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var stuck []string
+			for i, b := range blocks {
+				if !done[i] {
+					if next < 0 {
+						next = i
+					}
+					stuck = append(stuck, b.key)
+				}
+			}
+			sb.WriteString(fmt.Sprintf("%s dependency cycle between %s: %s is entered first, check the order manually\n",
+				comment, strings.Join(stuck, ", "), blocks[next].key))
+		}
+		done[next] = true
+		pending[blocks[next].key]--
+		sb.WriteString(blocks[next].text)
+	}
+}
+
+// renderBlock собирает текст блока через один из writeX-помощников.
+func renderBlock(write func(sb *strings.Builder)) string {
+	var sb strings.Builder
+	write(&sb)
+	return sb.String()
+}
+
+func interfaceKey(name string) string {
+	return "interface:" + name
+}
+
+// interfaceDeps перечисляет блоки, на которые ссылается интерфейс: VLAN (access,
+// trunk и Vlanif), ACL, процессы маршрутизации и родительский интерфейс подынтерфейса.
+func interfaceDeps(i model.Interface) []string {
+	var deps []string
+	if i.Vlan != 0 || i.TrunkVlans != "" || strings.HasPrefix(strings.ToLower(i.Name), "vlan") {
+		deps = append(deps, "vlans")
+	}
+	if i.ACLIn != "" || i.ACLOut != "" {
+		deps = append(deps, "acls")
+	}
+	if i.OSPF != nil || i.ISIS != nil {
+		deps = append(deps, "routing")
+	}
+	if parent, _, ok := strings.Cut(i.Name, "."); ok {
+		deps = append(deps, interfaceKey(parent))
+	}
+	return deps
+}
+
+// passiveDeps ставит passive-interface и silent-interface после процессов и
+// интерфейсов, на которые они ссылаются.
+func passiveDeps(cfg *model.Config) []string {
+	deps := []string{"routing"}
+	add := func(ifaces ...[]string) {
+		for _, list := range ifaces {
+			for _, name := range list {
+				deps = append(deps, interfaceKey(name))
+			}
+		}
+	}
+	for _, p := range cfg.OSPFProcesses {
+		add(p.PassiveInterfaces, p.NoPassiveInterfaces)
+	}
+	for _, p := range cfg.RIP {
+		add(p.PassiveInterfaces, p.NoPassiveInterfaces)
+	}
+	for _, p := range cfg.EIGRP {
+		add(p.PassiveInterfaces, p.NoPassiveInterfaces)
+	}
+	for _, p := range cfg.ISIS {
+		add(p.PassiveInterfaces)
+	}
+	return deps
+}
+
+// routeDeps ставит маршруты после интерфейсов, через которые они идут.
+func routeDeps(cfg *model.Config) []string {
+	var deps []string
+	for _, r := range cfg.Routes {
+		if r.Interface != "" {
+			deps = append(deps, interfaceKey(r.Interface))
+		}
+	}
+	return deps
+}
@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteBlocksOrdersByDependencies(t *testing.T) {
+	var sb strings.Builder
+	writeBlocks(&sb, []configBlock{
+		{key: "interface:Gi0/0", deps: []string{"acls"}, text: "iface\n"},
+		{key: "passive", deps: []string{"routing", "interface:Gi0/0"}, text: "passive\n"},
+		{key: "routing", text: "routing\n"},
+		{key: "acls", deps: []string{"missing"}, text: "acls\n"},
+	}, "!")
+	if got, want := sb.String(), "routing\nacls\niface\npassive\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteBlocksReportsCycle(t *testing.T) {
+	var sb strings.Builder
+	writeBlocks(&sb, []configBlock{
+		{key: "a", deps: []string{"b"}, text: "a\n"},
+		{key: "b", deps: []string{"a"}, text: "b\n"},
+	}, "#")
+	want := "# dependency cycle between a, b: a is entered first, check the order manually\na\nb\n"
+	if got := sb.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"converter/model"
+)
+
+func writeCiscoPolicies(sb *strings.Builder, cfg *model.Config) {
+	for _, pl := range cfg.PrefixLists {
+		seq := 5
+		for _, e := range pl.Entries {
+			entrySeq := e.Sequence
+			if entrySeq == 0 {
+				entrySeq = seq
+			}
+			line := fmt.Sprintf("ip prefix-list %s seq %d %s %s/%d", pl.Name, entrySeq, e.Action, e.Prefix, e.Length)
+			if e.GE != 0 {
+				line += fmt.Sprintf(" ge %d", e.GE)
+			}
+			if e.LE != 0 {
+				line += fmt.Sprintf(" le %d", e.LE)
+			}
+			sb.WriteString(line + "\n")
+			seq = entrySeq + 5
+		}
+	}
+	for _, rm := range cfg.RouteMaps {
+		for _, e := range rm.Entries {
+			sb.WriteString(fmt.Sprintf("route-map %s %s %d\n", rm.Name, e.Action, routeMapSequence(e)))
+			if len(e.MatchPrefixLists) > 0 {
+				sb.WriteString(fmt.Sprintf(" match ip address prefix-list %s\n", strings.Join(e.MatchPrefixLists, " ")))
+			}
+			if len(e.MatchACLs) > 0 {
+				refs := make([]string, 0, len(e.MatchACLs))
+				for _, ref := range e.MatchACLs {
+					refs = append(refs, ciscoACLRef(cfg, ref))
+				}
+				sb.WriteString(fmt.Sprintf(" match ip address %s\n", strings.Join(refs, " ")))
+			}
+			if len(e.MatchTags) > 0 {
+				sb.WriteString(fmt.Sprintf(" match tag %s\n", strings.Join(e.MatchTags, " ")))
+			}
+			if len(e.MatchInterfaces) > 0 {
+				sb.WriteString(fmt.Sprintf(" match interface %s\n", strings.Join(e.MatchInterfaces, " ")))
+			}
+			if e.SetMetric != "" {
+				sb.WriteString(fmt.Sprintf(" set metric %s\n", e.SetMetric))
+			}
+			if e.SetTag != "" {
+				sb.WriteString(fmt.Sprintf(" set tag %s\n", e.SetTag))
+			}
+			if e.SetLocalPreference != "" {
+				sb.WriteString(fmt.Sprintf(" set local-preference %s\n", e.SetLocalPreference))
+			}
+			if e.SetNextHop != "" {
+				sb.WriteString(fmt.Sprintf(" set ip next-hop %s\n", e.SetNextHop))
+			}
+			for _, raw := range e.Raw {
+				sb.WriteString(fmt.Sprintf(" ! unsupported clause: %s\n", raw))
+			}
+			sb.WriteString(" exit\n")
+		}
+	}
+}
+
+func writeHuaweiPolicies(sb *strings.Builder, cfg *model.Config) {
+	for _, pl := range cfg.PrefixLists {
+		index := 10
+		for _, e := range pl.Entries {
+			entryIndex := e.Sequence
+			if entryIndex == 0 {
+				entryIndex = index
+			}
+			line := fmt.Sprintf("ip ip-prefix %s index %d %s %s %d", pl.Name, entryIndex, e.Action, e.Prefix, e.Length)
+			if e.GE != 0 {
+				line += fmt.Sprintf(" greater-equal %d", e.GE)
+			}
+			if e.LE != 0 {
+				line += fmt.Sprintf(" less-equal %d", e.LE)
+			}
+			sb.WriteString(line + "\n")
+			index = entryIndex + 10
+		}
+	}
+	for _, rm := range cfg.RouteMaps {
+		for _, e := range rm.Entries {
+			sb.WriteString(fmt.Sprintf("route-policy %s %s node %d\n", rm.Name, e.Action, routeMapSequence(e)))
+			for _, name := range e.MatchPrefixLists {
+				sb.WriteString(fmt.Sprintf(" if-match ip-prefix %s\n", name))
+			}
+			for _, ref := range e.MatchACLs {
+				sb.WriteString(fmt.Sprintf(" if-match acl %s\n", huaweiACLRef(cfg, ref)))
+			}
+			for _, tag := range e.MatchTags {
+				sb.WriteString(fmt.Sprintf(" if-match tag %s\n", tag))
+			}
+			for _, iface := range e.MatchInterfaces {
+				sb.WriteString(fmt.Sprintf(" if-match interface %s\n", toHuaweiIfaceName(iface)))
+			}
+			if e.SetMetric != "" {
+				sb.WriteString(fmt.Sprintf(" apply cost %s\n", e.SetMetric))
+			}
+			if e.SetTag != "" {
+				sb.WriteString(fmt.Sprintf(" apply tag %s\n", e.SetTag))
+			}
+			if e.SetLocalPreference != "" {
+				sb.WriteString(fmt.Sprintf(" apply local-preference %s\n", e.SetLocalPreference))
+			}
+			if e.SetNextHop != "" {
+				sb.WriteString(fmt.Sprintf(" apply ip-address next-hop %s\n", e.SetNextHop))
+			}
+			for _, raw := range e.Raw {
+				sb.WriteString(fmt.Sprintf(" # unsupported clause: %s\n", raw))
+			}
+			sb.WriteString("quit\n\n")
+		}
+	}
+}
+
+func routeMapSequence(e model.RouteMapEntry) int {
+	if e.Sequence == 0 {
+		return 10
+	}
+	return e.Sequence
+}
+
+// ciscoACLRef возвращает ссылку на ACL так, как её пишет Cisco: именованные
+// списки остаются именованными, номера уже переведены при распределении.
+func ciscoACLRef(cfg *model.Config, ref string) string {
+	if acl, ok := cfg.FindACL(ref); ok {
+		return acl.Ref()
+	}
+	return ref
+}
+
+// huaweiACLRef возвращает номер ACL для ссылок Huawei, которые принимают только номер.
+func huaweiACLRef(cfg *model.Config, ref string) string {
+	if acl, ok := cfg.FindACL(ref); ok && acl.ID != 0 {
+		return strconv.Itoa(acl.ID)
+	}
+	return ref
+}
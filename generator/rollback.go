@@ -0,0 +1,310 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"converter/model"
+)
+
+// GenerateCiscoRollback формирует скрипт отката для результата GenerateCisco:
+// объекты удаляются в обратном порядке, логические интерфейсы удаляются целиком,
+// а на физических отменяется каждая добавленная команда.
+func GenerateCiscoRollback(cfg *model.Config) string {
+	var sb strings.Builder
+	sb.WriteString("configure terminal\n")
+
+	for i := len(cfg.StaticNAT) - 1; i >= 0; i-- {
+		sb.WriteString("no " + formatCiscoStaticNAT(cfg.StaticNAT[i]))
+	}
+	for i := len(cfg.NATRule) - 1; i >= 0; i-- {
+		sb.WriteString("no " + formatCiscoNATRule(cfg, cfg.NATRule[i]))
+	}
+	for i := len(cfg.Lines) - 1; i >= 0; i-- {
+		l := cfg.Lines[i]
+		if l.ACLIn == "" && l.ACLOut == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("line %s\n", formatLineRange(l)))
+		if l.ACLOut != "" {
+			sb.WriteString(fmt.Sprintf(" no access-class %s out\n", ciscoACLRef(cfg, l.ACLOut)))
+		}
+		if l.ACLIn != "" {
+			sb.WriteString(fmt.Sprintf(" no access-class %s in\n", ciscoACLRef(cfg, l.ACLIn)))
+		}
+		sb.WriteString(" exit\n")
+	}
+	if cfg.DeviceType == "cisco" {
+		for i := len(cfg.VRFRoutes) - 1; i >= 0; i-- {
+			sb.WriteString("no " + cfg.VRFRoutes[i] + "\n")
+		}
+	}
+	for i := len(cfg.Routes) - 1; i >= 0; i-- {
+		sb.WriteString("no " + lastLine(formatCiscoRoute(cfg.Routes[i])) + "\n")
+	}
+
+	// интерфейсы освобождаются раньше ACL и процессов, на которые они ссылаются
+	for i := len(cfg.Interfaces) - 1; i >= 0; i-- {
+		iface := cfg.Interfaces[i]
+		if isLogicalInterface(iface.Name) {
+			sb.WriteString(fmt.Sprintf("no interface %s\n", iface.Name))
+			continue
+		}
+		var block strings.Builder
+		writeCiscoInterface(&block, cfg, iface)
+		writeUndoBlock(&sb, block.String(), "!", " exit", undoCiscoLine)
+	}
+
+	for i := len(cfg.ISIS) - 1; i >= 0; i-- {
+		if tag := cfg.ISIS[i].Tag; tag != "" {
+			sb.WriteString(fmt.Sprintf("no router isis %s\n", tag))
+		} else {
+			sb.WriteString("no router isis\n")
+		}
+	}
+	for i := len(cfg.EIGRP) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("no router eigrp %d\n", cfg.EIGRP[i].AS))
+	}
+	if len(cfg.RIP) > 0 {
+		sb.WriteString("no router rip\n")
+	}
+	for i := len(cfg.OSPFProcesses) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("no router ospf %d\n", cfg.OSPFProcesses[i].ProcessID))
+	}
+	for i := len(cfg.NATPool) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("no ip nat pool %s\n", cfg.NATPool[i].Name))
+	}
+	for i := len(cfg.RouteMaps) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("no route-map %s\n", cfg.RouteMaps[i].Name))
+	}
+	for i := len(cfg.PrefixLists) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("no ip prefix-list %s\n", cfg.PrefixLists[i].Name))
+	}
+
+	for i := len(cfg.ACLs) - 1; i >= 0; i-- {
+		acl := cfg.ACLs[i]
+		if acl.Name == "" {
+			sb.WriteString(fmt.Sprintf("no access-list %d\n", acl.ID))
+		} else {
+			sb.WriteString(fmt.Sprintf("no ip access-list %s %s\n", ciscoACLKind(acl), acl.Name))
+		}
+	}
+	for i := len(cfg.ObjectGroups) - 1; i >= 0; i-- {
+		g := cfg.ObjectGroups[i]
+		sb.WriteString(fmt.Sprintf("no object-group %s %s\n", g.Type, g.Name))
+	}
+	for i := len(cfg.TimeRanges) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("no time-range %s\n", cfg.TimeRanges[i].Name))
+	}
+
+	for i := len(cfg.DHCP.Pools) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("no ip dhcp pool %s\n", cfg.DHCP.Pools[i].Name))
+	}
+	for i := len(cfg.DHCP.Excluded) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("no ip dhcp excluded-address %s\n", formatDHCPExcluded(cfg.DHCP.Excluded[i])))
+	}
+	if cfg.Service.FTP {
+		sb.WriteString("no ip ftp server enable\n")
+	}
+	if cfg.Service.SMTP {
+		sb.WriteString("no ip smtp server\n")
+	}
+	if cfg.STP.Mode != "" {
+		sb.WriteString(fmt.Sprintf("no spanning-tree mode %s\n", cfg.STP.Mode))
+	}
+	for i := len(cfg.Vlans) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("no vlan %d\n", cfg.Vlans[i].ID))
+	}
+
+	sb.WriteString("end\n")
+	return sb.String()
+}
+
+// GenerateHuaweiRollback — то же, что GenerateCiscoRollback, для результата GenerateHuawei.
+func GenerateHuaweiRollback(cfg *model.Config) string {
+	var sb strings.Builder
+	sb.WriteString("system-view\n")
+
+	if cfg.Service.FTP {
+		sb.WriteString("undo ftp server enable\n")
+	}
+	if cfg.Service.SMTP {
+		sb.WriteString("undo smtp server enable\n")
+	}
+	if cfg.STP.Mode != "" {
+		sb.WriteString("undo stp mode\n")
+	}
+
+	for i := len(cfg.Lines) - 1; i >= 0; i-- {
+		l := cfg.Lines[i]
+		if l.ACLIn == "" && l.ACLOut == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("user-interface %s\n", formatLineRange(l)))
+		if l.ACLOut != "" {
+			sb.WriteString(" undo acl outbound\n")
+		}
+		if l.ACLIn != "" {
+			sb.WriteString(" undo acl inbound\n")
+		}
+		sb.WriteString("quit\n")
+	}
+	if cfg.DeviceType == "huawei" {
+		for i := len(cfg.VRFRoutes) - 1; i >= 0; i-- {
+			sb.WriteString("undo " + cfg.VRFRoutes[i] + "\n")
+		}
+	}
+	for i := len(cfg.Routes) - 1; i >= 0; i-- {
+		sb.WriteString("undo " + lastLine(formatHuaweiRoute(cfg.Routes[i])) + "\n")
+	}
+
+	// интерфейсы освобождаются раньше ACL и пулов NAT, которые на них ссылаются
+	poolIDs := huaweiNATPoolIDs(cfg)
+	natIfaces, natLines, _ := huaweiNATInterfaces(cfg, poolIDs)
+	ifaces := interfacesByName(cfg)
+	for i := len(natIfaces) - 1; i >= 0; i-- {
+		if _, ok := ifaces[natIfaces[i]]; ok {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("interface %s\n", toHuaweiIfaceName(natIfaces[i])))
+		lines := natLines[natIfaces[i]]
+		for j := len(lines) - 1; j >= 0; j-- {
+			sb.WriteString(" undo" + lines[j])
+		}
+		sb.WriteString("quit\n")
+	}
+	for i := len(cfg.Interfaces) - 1; i >= 0; i-- {
+		iface := cfg.Interfaces[i]
+		if isLogicalInterface(iface.Name) {
+			sb.WriteString(fmt.Sprintf("undo interface %s\n", toHuaweiIfaceName(iface.Name)))
+			continue
+		}
+		var block strings.Builder
+		writeHuaweiInterface(&block, cfg, iface, natLines[iface.Name])
+		writeUndoBlock(&sb, block.String(), "#", "quit", undoHuaweiLine)
+	}
+
+	for i := len(cfg.ISIS) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("undo isis %d\n", huaweiISISProcessID(cfg, cfg.ISIS[i].Tag)))
+	}
+	for i := len(cfg.RIP) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("undo rip %d\n", max(1, cfg.RIP[i].ProcessID)))
+	}
+	for i := len(cfg.OSPFProcesses) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("undo ospf %d\n", cfg.OSPFProcesses[i].ProcessID))
+	}
+	for i := len(cfg.NATPool) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("undo nat address-group %d\n", poolIDs[cfg.NATPool[i].Name]))
+	}
+	for i := len(cfg.RouteMaps) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("undo route-policy %s\n", cfg.RouteMaps[i].Name))
+	}
+	for i := len(cfg.PrefixLists) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("undo ip ip-prefix %s\n", cfg.PrefixLists[i].Name))
+	}
+
+	for i := len(cfg.ACLs) - 1; i >= 0; i-- {
+		acl := cfg.ACLs[i]
+		if acl.Name != "" {
+			sb.WriteString(fmt.Sprintf("undo acl name %s\n", acl.Name))
+		} else {
+			sb.WriteString(fmt.Sprintf("undo acl number %d\n", acl.ID))
+		}
+	}
+	for i := len(cfg.ObjectGroups) - 1; i >= 0; i-- {
+		g := cfg.ObjectGroups[i]
+		if g.Type == "network" {
+			sb.WriteString(fmt.Sprintf("undo ip address-set %s\n", g.Name))
+		} else {
+			sb.WriteString(fmt.Sprintf("undo ip service-set %s\n", g.Name))
+		}
+	}
+	for i := len(cfg.TimeRanges) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("undo time-range %s\n", cfg.TimeRanges[i].Name))
+	}
+
+	if !cfg.DHCP.Empty() {
+		for i := len(cfg.DHCP.Pools) - 1; i >= 0; i-- {
+			if cfg.DHCP.Pools[i].Interface == "" {
+				sb.WriteString(fmt.Sprintf("undo ip pool %s\n", cfg.DHCP.Pools[i].Name))
+			}
+		}
+		sb.WriteString("undo dhcp enable\n")
+	}
+	if len(cfg.Vlans) > 0 {
+		sb.WriteString("undo vlan batch")
+		for _, v := range cfg.Vlans {
+			sb.WriteString(fmt.Sprintf(" %d", v.ID))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("return\n")
+	return sb.String()
+}
+
+// writeUndoBlock отменяет команды блока интерфейса в обратном порядке; строки
+// комментариев (начинающиеся с comment) пропускаются.
+func writeUndoBlock(sb *strings.Builder, block, comment, exit string, undo func(string) string) {
+	var header string
+	var lines []string
+	for _, line := range strings.Split(block, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), comment):
+		case header == "":
+			header = line
+		case line == exit:
+		default:
+			lines = append(lines, undo(strings.TrimSpace(line)))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	sb.WriteString(header + "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		sb.WriteString(" " + lines[i] + "\n")
+	}
+	sb.WriteString(exit + "\n")
+}
+
+func undoCiscoLine(line string) string {
+	return "no " + line
+}
+
+// huaweiUndoWords — сколько слов команды оставлять после "undo": VRP не принимает
+// значение параметра в undo-форме этих команд. Остальные команды отменяются целиком.
+var huaweiUndoWords = map[string]int{
+	"description":              1,
+	"bandwidth":                1,
+	"vlan-type":                2,
+	"port link-type":           2,
+	"port default vlan":        3,
+	"traffic-filter":           2,
+	"dhcp select":              2,
+	"dhcp server dns-list":     3,
+	"dhcp server domain-name":  3,
+	"dhcp server lease":        3,
+	"ospf network-type":        2,
+	"ospf cost":                2,
+	"ospf dr-priority":         2,
+	"ospf timer hello":         3,
+	"ospf timer dead":          3,
+	"ospf authentication-mode": 2,
+	"isis circuit-level":       2,
+	"isis cost":                2,
+	"isis circuit-type":        2,
+	"nat address-group":        3,
+}
+
+func undoHuaweiLine(line string) string {
+	fields := strings.Fields(line)
+	keep := len(fields)
+	best := 0
+	for prefix, n := range huaweiUndoWords {
+		if (line == prefix || strings.HasPrefix(line, prefix+" ")) && len(prefix) > best {
+			keep, best = n, len(prefix)
+		}
+	}
+	return "undo " + strings.Join(fields[:keep], " ")
+}
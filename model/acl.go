@@ -0,0 +1,217 @@
+package model
+
+import "strconv"
+
+type ACLRule struct {
+	Sequence int `json:"sequence,omitempty"`
+	// Action is permit, deny or remark. Remark entries carry only Remark and
+	// precede the rule they describe, so their position in Rules is kept.
+	Action   string `json:"action"`
+	Remark   string `json:"remark,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Wildcard string `json:"wildcard,omitempty"`
+	SrcPort  string `json:"src_port,omitempty"`
+
+	Destination string `json:"destination,omitempty"`
+	DstWildcard string `json:"dst_wildcard,omitempty"`
+	DstPort     string `json:"dst_port,omitempty"`
+
+	// SourceGroup and DestinationGroup replace Source/Destination with a network
+	// object-group; ServiceGroup replaces Protocol and ports with a service object-group.
+	SourceGroup      string `json:"source_group,omitempty"`
+	DestinationGroup string `json:"destination_group,omitempty"`
+	ServiceGroup     string `json:"service_group,omitempty"`
+
+	// ICMPType holds a Cisco ICMP message name or type number; ICMPCode is the numeric code.
+	ICMPType    string `json:"icmp_type,omitempty"`
+	ICMPCode    string `json:"icmp_code,omitempty"`
+	Established bool   `json:"established,omitempty"`
+	Fragments   bool   `json:"fragments,omitempty"`
+	DSCP        string `json:"dscp,omitempty"`
+	Precedence  string `json:"precedence,omitempty"`
+	TOS         string `json:"tos,omitempty"`
+	TimeRange   string `json:"time_range,omitempty"`
+	Log         bool   `json:"log,omitempty"`
+
+	// Raw keeps the whole rule text when it could not be parsed.
+	Raw string `json:"raw,omitempty"`
+}
+
+// ACL is a numbered or named access list. Named ACLs keep ID 0 unless the
+// source also assigned a number (Huawei "acl name <name> <number>").
+type ACL struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+	// Description is the Huawei "description" of the ACL view.
+	Description string    `json:"description,omitempty"`
+	Rules       []ACLRule `json:"rules,omitempty"`
+}
+
+// ObjectGroup is a Cisco "object-group network|service" or a Huawei
+// "ip address-set" / "ip service-set".
+type ObjectGroup struct {
+	Name string `json:"name"`
+	// Type is network or service.
+	Type        string         `json:"type"`
+	Description string         `json:"description,omitempty"`
+	Addresses   []GroupAddress `json:"addresses,omitempty"`
+	Services    []GroupService `json:"services,omitempty"`
+	// Groups are nested groups of the same type ("group-object").
+	Groups []string `json:"groups,omitempty"`
+}
+
+// GroupAddress is a host or subnet (Address with Wildcard) or a range from Address to End.
+type GroupAddress struct {
+	Address  string `json:"address"`
+	Wildcard string `json:"wildcard,omitempty"`
+	End      string `json:"end,omitempty"`
+}
+
+// GroupService is one protocol/port entry; ports use the ACLRule port syntax.
+type GroupService struct {
+	Protocol string `json:"protocol"`
+	SrcPort  string `json:"src_port,omitempty"`
+	DstPort  string `json:"dst_port,omitempty"`
+	ICMPType string `json:"icmp_type,omitempty"`
+}
+
+// TimeRange is a named schedule referenced by ACLRule.TimeRange.
+type TimeRange struct {
+	Name     string         `json:"name"`
+	Periodic []PeriodicTime `json:"periodic,omitempty"`
+	Absolute *AbsoluteTime  `json:"absolute,omitempty"`
+	// Raw keeps entries that have no equivalent on the other vendor.
+	Raw []string `json:"raw,omitempty"`
+}
+
+// PeriodicTime repeats on the given days between Start and End ("HH:MM").
+// Days are daily, weekdays, weekend or mon..sun.
+type PeriodicTime struct {
+	Days  []string `json:"days"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// AbsoluteTime is a one-off period; dates are "YYYY-MM-DD", times "HH:MM".
+type AbsoluteTime struct {
+	StartDate string `json:"start_date,omitempty"`
+	StartTime string `json:"start_time,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+	EndTime   string `json:"end_time,omitempty"`
+}
+
+// Line is a Cisco "line" or a Huawei "user-interface" range with its access ACLs.
+type Line struct {
+	// Type is vty, con or aux.
+	Type   string `json:"type"`
+	First  int    `json:"first"`
+	Last   int    `json:"last,omitempty"`
+	ACLIn  string `json:"acl_in,omitempty"`
+	ACLOut string `json:"acl_out,omitempty"`
+}
+
+// Ref returns the reference other objects use for the ACL: its name or its number.
+func (a ACL) Ref() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return strconv.Itoa(a.ID)
+}
+
+// ACLRef returns the ACL reference of the NAT policy.
+func (p NATPolicy) ACLRef() string {
+	if p.ACLName != "" {
+		return p.ACLName
+	}
+	return strconv.Itoa(p.ACLID)
+}
+
+func (c *Config) TimeRangeRef(name string) *TimeRange {
+	for i := range c.TimeRanges {
+		if c.TimeRanges[i].Name == name {
+			return &c.TimeRanges[i]
+		}
+	}
+	c.TimeRanges = append(c.TimeRanges, TimeRange{Name: name})
+	return &c.TimeRanges[len(c.TimeRanges)-1]
+}
+
+func (c *Config) ObjectGroupRef(name, groupType string) *ObjectGroup {
+	for i := range c.ObjectGroups {
+		if c.ObjectGroups[i].Name == name {
+			return &c.ObjectGroups[i]
+		}
+	}
+	c.ObjectGroups = append(c.ObjectGroups, ObjectGroup{Name: name, Type: groupType})
+	return &c.ObjectGroups[len(c.ObjectGroups)-1]
+}
+
+func (c *Config) FindObjectGroup(name string) (ObjectGroup, bool) {
+	for _, g := range c.ObjectGroups {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return ObjectGroup{}, false
+}
+
+// FindACL looks an ACL up by name or number.
+func (c *Config) FindACL(ref string) (ACL, bool) {
+	for _, a := range c.ACLs {
+		if a.Name == ref || (a.Name == "" && strconv.Itoa(a.ID) == ref) {
+			return a, true
+		}
+	}
+	if id, err := strconv.Atoi(ref); err == nil && id != 0 {
+		for _, a := range c.ACLs {
+			if a.ID == id {
+				return a, true
+			}
+		}
+	}
+	return ACL{}, false
+}
+
+// DefaultACLID translates an ACL number into the numbering of the target
+// vendor ("cisco" or "huawei") with fixed offsets: Cisco standard N becomes
+// Huawei basic 2000+N, Cisco extended 100-199 and 2000-2699 become 3000-3099
+// and 3000-3699. The result may fall outside ACLIDRanges or collide with
+// another ACL; the caller is expected to check.
+func DefaultACLID(vendor string, id int, aclType string) int {
+	if vendor == "huawei" {
+		if aclType == "extended" && ((id >= 100 && id <= 199) || (id >= 2000 && id <= 2699)) {
+			if id >= 2000 {
+				return id + 1000
+			}
+			return id + 2900
+		}
+		if (aclType == "standard" || aclType == "basic" || aclType == "") && id >= 1 && id <= 1999 {
+			return 2000 + id
+		}
+		return id
+	}
+	if aclType == "advanced" && id >= 3000 && id <= 3999 {
+		return id - 1000
+	}
+	if (aclType == "basic" || aclType == "standard" || aclType == "") && id >= 2000 && id <= 2999 {
+		return id - 2000
+	}
+	return id
+}
+
+// ACLIDRanges returns the number ranges the vendor accepts for an ACL type.
+func ACLIDRanges(vendor, aclType string) [][2]int {
+	extended := aclType == "extended" || aclType == "advanced"
+	if vendor == "huawei" {
+		if extended {
+			return [][2]int{{3000, 3999}}
+		}
+		return [][2]int{{2000, 2999}}
+	}
+	if extended {
+		return [][2]int{{100, 199}, {2000, 2699}}
+	}
+	return [][2]int{{1, 99}, {1300, 1999}}
+}
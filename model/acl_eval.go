@@ -0,0 +1,147 @@
+package model
+
+import "strings"
+
+// Flow is a single packet description for ACL evaluation. Ports of zero mean
+// "not given": such a flow only matches rules without a port condition.
+type Flow struct {
+	Protocol    string
+	Source      string
+	SrcPort     int
+	Destination string
+	DstPort     int
+	ICMPType    string
+	Established bool
+	Fragment    bool
+	DSCP        string
+}
+
+// ACLVerdict is the result of evaluating a flow against an ACL.
+type ACLVerdict struct {
+	// Action is permit or deny, or unknown when an unparsed rule was reached
+	// before any rule matched.
+	Action string
+	// Index is the position of Rule in ACL.Rules, -1 for the implicit deny.
+	Index int
+	Rule  ACLRule
+}
+
+// EvaluateACL returns the first rule of the ACL that matches the flow, the way
+// the device does. Rules with a time-range are treated as active; rules
+// matching on precedence or TOS only match packets that carry those values,
+// which a Flow cannot describe, so they never match.
+func (c *Config) EvaluateACL(acl ACL, flow Flow) ACLVerdict {
+	for i, rule := range acl.Rules {
+		if rule.Action == "remark" {
+			continue
+		}
+		if rule.Raw != "" {
+			return ACLVerdict{Action: "unknown", Index: i, Rule: rule}
+		}
+		if c.ruleMatchesFlow(rule, flow) {
+			return ACLVerdict{Action: rule.Action, Index: i, Rule: rule}
+		}
+	}
+	return ACLVerdict{Action: "deny", Index: -1}
+}
+
+func (c *Config) ruleMatchesFlow(rule ACLRule, flow Flow) bool {
+	proto := CanonicalProtocol(flow.Protocol)
+	if rule.ServiceGroup != "" {
+		if !c.serviceGroupMatches(rule.ServiceGroup, proto, flow, map[string]bool{}) {
+			return false
+		}
+	} else {
+		if rule.Protocol != "" && rule.Protocol != "ip" && CanonicalProtocol(rule.Protocol) != proto {
+			return false
+		}
+		if !portMatches(proto, rule.SrcPort, flow.SrcPort) || !portMatches(proto, rule.DstPort, flow.DstPort) {
+			return false
+		}
+		if rule.ICMPType != "" && !strings.EqualFold(rule.ICMPType, flow.ICMPType) {
+			return false
+		}
+	}
+	if rule.Established && !flow.Established || rule.Fragments && !flow.Fragment {
+		return false
+	}
+	if rule.DSCP != "" && !strings.EqualFold(rule.DSCP, flow.DSCP) || rule.Precedence != "" || rule.TOS != "" {
+		return false
+	}
+	return c.addressMatches(rule.SourceGroup, rule.Source, rule.Wildcard, flow.Source) &&
+		c.addressMatches(rule.DestinationGroup, rule.Destination, rule.DstWildcard, flow.Destination)
+}
+
+func (c *Config) addressMatches(group, addr, wildcard, ip string) bool {
+	if group != "" {
+		return c.networkGroupMatches(group, ip, map[string]bool{})
+	}
+	addr, wildcard = matchAddress(addr, wildcard)
+	return WildcardContains(addr, wildcard, ip, "0.0.0.0")
+}
+
+func (c *Config) networkGroupMatches(name, ip string, seen map[string]bool) bool {
+	g, ok := c.FindObjectGroup(name)
+	if !ok || seen[name] {
+		return false
+	}
+	seen[name] = true
+	for _, a := range g.Addresses {
+		if a.End != "" {
+			v, _ := ParseIPv4(ip)
+			start, _ := ParseIPv4(a.Address)
+			end, _ := ParseIPv4(a.End)
+			if v >= start && v <= end {
+				return true
+			}
+			continue
+		}
+		if addr, wildcard := matchAddress(a.Address, a.Wildcard); WildcardContains(addr, wildcard, ip, "0.0.0.0") {
+			return true
+		}
+	}
+	for _, nested := range g.Groups {
+		if c.networkGroupMatches(nested, ip, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) serviceGroupMatches(name, proto string, flow Flow, seen map[string]bool) bool {
+	g, ok := c.FindObjectGroup(name)
+	if !ok || seen[name] {
+		return false
+	}
+	seen[name] = true
+	for _, svc := range g.Services {
+		tcpUDP := svc.Protocol == "tcp-udp" && (proto == "tcp" || proto == "udp")
+		if svc.Protocol != "ip" && svc.Protocol != proto && !tcpUDP {
+			continue
+		}
+		if !portMatches(proto, svc.SrcPort, flow.SrcPort) || !portMatches(proto, svc.DstPort, flow.DstPort) {
+			continue
+		}
+		if svc.ICMPType != "" && !strings.EqualFold(svc.ICMPType, flow.ICMPType) {
+			continue
+		}
+		return true
+	}
+	for _, nested := range g.Groups {
+		if c.serviceGroupMatches(nested, proto, flow, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func portMatches(proto, spec string, port int) bool {
+	if spec == "" {
+		return true
+	}
+	if port == 0 {
+		return false
+	}
+	ranges, ok := portRanges(proto, spec)
+	return ok && rangesContain(ranges, [][2]int{{port, port}})
+}
@@ -0,0 +1,243 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ACLFinding describes a rule that can be removed or needs review.
+// Rule and Other are indexes into ACL.Rules.
+type ACLFinding struct {
+	// Kind is shadowed (never matches: an earlier rule with the other action
+	// covers it), redundant (removing it changes nothing) or conflict (an
+	// earlier rule with the other action matches part of its traffic while
+	// neither rule covers the other).
+	Kind  string
+	Rule  int
+	Other int
+}
+
+// aclMatch is the traffic a rule matches, in a form that can be compared.
+type aclMatch struct {
+	proto      string
+	src, srcWC string
+	dst, dstWC string
+	srcPorts   [][2]int
+	dstPorts   [][2]int
+	qualifiers map[string]string
+}
+
+// LintACL finds shadowed, redundant and conflicting rules. Rules are compared
+// pairwise; remarks, unparsed rules and rules referencing object-groups are
+// skipped, as are rules with port keywords that have no known number.
+func LintACL(acl ACL) []ACLFinding {
+	matches := make([]*aclMatch, len(acl.Rules))
+	for i, rule := range acl.Rules {
+		matches[i] = ruleMatch(rule)
+	}
+
+	var findings []ACLFinding
+	dead := make(map[int]bool)
+	for i, b := range matches {
+		if b == nil {
+			continue
+		}
+		found := false
+		for j := 0; j < i && !found; j++ {
+			a := matches[j]
+			if a == nil || dead[j] || !a.contains(b) {
+				continue
+			}
+			kind := "redundant"
+			if acl.Rules[j].Action != acl.Rules[i].Action {
+				kind = "shadowed"
+			}
+			findings = append(findings, ACLFinding{Kind: kind, Rule: i, Other: j})
+			dead[i] = true
+			found = true
+		}
+		if found {
+			continue
+		}
+		// a later rule with the same action makes this one redundant when no
+		// rule in between treats any of its traffic differently
+		for k := i + 1; k < len(matches) && !found; k++ {
+			c := matches[k]
+			if c == nil {
+				// an unparsed rule in between may match anything
+				if acl.Rules[k].Action != "remark" {
+					break
+				}
+				continue
+			}
+			if acl.Rules[k].Action != acl.Rules[i].Action {
+				if c.overlaps(b) {
+					break
+				}
+				continue
+			}
+			if c.contains(b) {
+				findings = append(findings, ACLFinding{Kind: "redundant", Rule: i, Other: k})
+				dead[i] = true
+				found = true
+			}
+		}
+		if found {
+			continue
+		}
+		for j := 0; j < i; j++ {
+			a := matches[j]
+			if a == nil || dead[j] || acl.Rules[j].Action == acl.Rules[i].Action {
+				continue
+			}
+			// a later rule that covers the earlier one is the usual
+			// exception-then-general pattern, only partial overlaps are reported
+			if a.overlaps(b) && !b.contains(a) {
+				findings = append(findings, ACLFinding{Kind: "conflict", Rule: i, Other: j})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+func ruleMatch(rule ACLRule) *aclMatch {
+	if rule.Raw != "" || rule.Action == "remark" || rule.SourceGroup != "" || rule.DestinationGroup != "" || rule.ServiceGroup != "" {
+		return nil
+	}
+	m := &aclMatch{
+		proto:      rule.Protocol,
+		qualifiers: make(map[string]string),
+	}
+	if m.proto == "" {
+		m.proto = "ip"
+	}
+	m.src, m.srcWC = matchAddress(rule.Source, rule.Wildcard)
+	m.dst, m.dstWC = matchAddress(rule.Destination, rule.DstWildcard)
+	var ok bool
+	if m.srcPorts, ok = portRanges(m.proto, rule.SrcPort); !ok {
+		return nil
+	}
+	if m.dstPorts, ok = portRanges(m.proto, rule.DstPort); !ok {
+		return nil
+	}
+	for key, value := range map[string]string{
+		"icmp-type":   strings.TrimSpace(rule.ICMPType + " " + rule.ICMPCode),
+		"established": strconv.FormatBool(rule.Established),
+		"fragments":   strconv.FormatBool(rule.Fragments),
+		"dscp":        rule.DSCP,
+		"precedence":  rule.Precedence,
+		"tos":         rule.TOS,
+		"time-range":  rule.TimeRange,
+	} {
+		if value != "" && value != "false" {
+			m.qualifiers[key] = value
+		}
+	}
+	return m
+}
+
+func matchAddress(addr, wildcard string) (string, string) {
+	if addr == "" || strings.EqualFold(addr, "any") {
+		return "0.0.0.0", "255.255.255.255"
+	}
+	if wildcard == "" {
+		wildcard = "0.0.0.0"
+	}
+	return addr, wildcard
+}
+
+// portRanges turns a port spec into inclusive ranges; an empty spec is any port.
+func portRanges(proto, spec string) ([][2]int, bool) {
+	parts := strings.Fields(spec)
+	if len(parts) == 0 {
+		return [][2]int{{0, 65535}}, true
+	}
+	var nums []int
+	for _, p := range parts[1:] {
+		n, ok := PortNumber("", proto, p)
+		if !ok {
+			return nil, false
+		}
+		nums = append(nums, n)
+	}
+	switch {
+	case parts[0] == "eq" && len(nums) >= 1:
+		var result [][2]int
+		for _, n := range nums {
+			result = append(result, [2]int{n, n})
+		}
+		return result, true
+	case parts[0] == "neq" && len(nums) == 1:
+		var result [][2]int
+		if nums[0] > 0 {
+			result = append(result, [2]int{0, nums[0] - 1})
+		}
+		if nums[0] < 65535 {
+			result = append(result, [2]int{nums[0] + 1, 65535})
+		}
+		return result, true
+	case parts[0] == "gt" && len(nums) == 1:
+		return [][2]int{{nums[0] + 1, 65535}}, true
+	case parts[0] == "lt" && len(nums) == 1:
+		return [][2]int{{0, nums[0] - 1}}, true
+	case parts[0] == "range" && len(nums) == 2:
+		return [][2]int{{nums[0], nums[1]}}, true
+	}
+	return nil, false
+}
+
+// contains reports whether a matches all traffic b matches.
+func (a *aclMatch) contains(b *aclMatch) bool {
+	if a.proto != "ip" && a.proto != b.proto {
+		return false
+	}
+	for key, value := range a.qualifiers {
+		if b.qualifiers[key] != value {
+			return false
+		}
+	}
+	return WildcardContains(a.src, a.srcWC, b.src, b.srcWC) &&
+		WildcardContains(a.dst, a.dstWC, b.dst, b.dstWC) &&
+		rangesContain(a.srcPorts, b.srcPorts) &&
+		rangesContain(a.dstPorts, b.dstPorts)
+}
+
+// overlaps reports whether some traffic may match both a and b. Qualifiers
+// are ignored, so the answer errs on the side of reporting an overlap.
+func (a *aclMatch) overlaps(b *aclMatch) bool {
+	if a.proto != "ip" && b.proto != "ip" && a.proto != b.proto {
+		return false
+	}
+	return WildcardOverlaps(a.src, a.srcWC, b.src, b.srcWC) &&
+		WildcardOverlaps(a.dst, a.dstWC, b.dst, b.dstWC) &&
+		rangesOverlap(a.srcPorts, b.srcPorts) &&
+		rangesOverlap(a.dstPorts, b.dstPorts)
+}
+
+func rangesContain(outer, inner [][2]int) bool {
+	for _, in := range inner {
+		covered := false
+		for _, out := range outer {
+			if in[0] >= out[0] && in[1] <= out[1] {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+func rangesOverlap(a, b [][2]int) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x[0] <= y[1] && y[0] <= x[1] {
+				return true
+			}
+		}
+	}
+	return false
+}
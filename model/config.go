@@ -7,8 +7,24 @@ type Interface struct {
 	IP          string `json:"ip,omitempty"`
 
 	TrunkVlans string `json:"trunk_vlans,omitempty"`
+
+	// Bandwidth is the configured interface bandwidth in kbit/s.
+	Bandwidth int `json:"bandwidth,omitempty"`
+
+	// ACLIn and ACLOut reference the ACLs filtering traffic on the interface
+	// ("ip access-group" / "traffic-filter").
+	ACLIn  string `json:"acl_in,omitempty"`
+	ACLOut string `json:"acl_out,omitempty"`
+
+	// NAT is the interface NAT role: "inside", "outside" or empty.
+	NAT string `json:"nat,omitempty"`
+
+	OSPF *InterfaceOSPF `json:"ospf,omitempty"`
+	ISIS *InterfaceISIS `json:"isis,omitempty"`
 }
 
+// OSPF is a legacy flat OSPF network statement.
+// Deprecated: use OSPFProcess; MigrateLegacy converts old models.
 type OSPF struct {
 	ProcessID int    `json:"process_id"`
 	Network   string `json:"network"`
@@ -22,41 +38,58 @@ type Vlan struct {
 	TrunkVlans string `json:"trunk_vlans,omitempty"`
 }
 
+// Route is a static route. Gateway is the next-hop address, Interface the outgoing
+// interface ("Null0" for blackhole routes); Distance is the Cisco administrative
+// distance or the Huawei preference, Name the Cisco name or the Huawei description.
 type Route struct {
 	Destination string `json:"destination"`
 	Mask        string `json:"mask"`
-	Gateway     string `json:"gateway"`
+	Gateway     string `json:"gateway,omitempty"`
+	Interface   string `json:"interface,omitempty"`
+	Distance    int    `json:"distance,omitempty"`
+	Tag         int    `json:"tag,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Permanent   bool   `json:"permanent,omitempty"`
+	Track       string `json:"track,omitempty"`
 }
 
+// NAT is the legacy inside/outside interface pair kept for JSON backward compatibility.
+// Deprecated: use Interface.NAT; MigrateLegacy converts old models.
 type NAT struct {
 	Inside  string `json:"inside"`
 	Outside string `json:"outside"`
 }
 
+// NATPolicy транслирует адреса из ACL либо в адрес внешнего интерфейса (Outside), либо в пул (Pool).
 type NATPolicy struct {
-	ACLID    int    `json:"acl_id"`
-	Outside  string `json:"outside"`
+	ACLID int `json:"acl_id,omitempty"`
+	// ACLName references a named ACL instead of ACLID.
+	ACLName  string `json:"acl_name,omitempty"`
+	Outside  string `json:"outside,omitempty"`
+	Pool     string `json:"pool,omitempty"`
 	Overload bool   `json:"overload,omitempty"`
 }
 
-type ACLRule struct {
-	Sequence int    `json:"sequence,omitempty"`
-	Action   string `json:"action"`
-	Protocol string `json:"protocol,omitempty"`
-	Source   string `json:"source,omitempty"`
-	Wildcard string `json:"wildcard,omitempty"`
-	SrcPort  string `json:"src_port,omitempty"`
-
-	Destination string `json:"destination,omitempty"`
-	DstWildcard string `json:"dst_wildcard,omitempty"`
-	DstPort     string `json:"dst_port,omitempty"`
-	Raw         string `json:"raw,omitempty"`
+// NATPool is a Cisco "ip nat pool" or a Huawei "nat address-group".
+// Name is the reference key used by NATPolicy.Pool; ID is the Huawei group index if known.
+type NATPool struct {
+	Name  string `json:"name"`
+	ID    int    `json:"id,omitempty"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Mask  string `json:"mask,omitempty"`
 }
 
-type ACL struct {
-	ID    int       `json:"id"`
-	Type  string    `json:"type,omitempty"`
-	Rules []ACLRule `json:"rules,omitempty"`
+// StaticNAT описывает статическую трансляцию один-к-одному или проброс порта.
+type StaticNAT struct {
+	Protocol        string `json:"protocol,omitempty"`
+	InsideAddress   string `json:"inside_address"`
+	InsidePort      string `json:"inside_port,omitempty"`
+	GlobalAddress   string `json:"global_address,omitempty"`
+	GlobalInterface string `json:"global_interface,omitempty"`
+	GlobalPort      string `json:"global_port,omitempty"`
+	// Interface is the outside interface the mapping is applied on (Huawei "nat server"/"nat static").
+	Interface string `json:"interface,omitempty"`
 }
 
 type Config struct {
@@ -64,16 +97,36 @@ type Config struct {
 	Vlans      []Vlan      `json:"vlans,omitempty"`
 	Interfaces []Interface `json:"interfaces,omitempty"`
 	Routes     []Route     `json:"routes,omitempty"`
+	// VRFRoutes keeps static routes of a VRF (Huawei VPN instance) as source
+	// lines: VRFs are not modelled.
+	VRFRoutes []string `json:"vrf_routes,omitempty"`
+
+	OSPFProcesses []OSPFProcess  `json:"ospf_processes,omitempty"`
+	RIP           []RIPProcess   `json:"rip,omitempty"`
+	EIGRP         []EIGRPProcess `json:"eigrp,omitempty"`
+	ISIS          []ISISProcess  `json:"isis,omitempty"`
 
-	OSPF    []OSPF      `json:"ospf,omitempty"`
 	NAT     []NAT       `json:"nat,omitempty"`
 	NATRule []NATPolicy `json:"nat_rule,omitempty"`
+	NATPool []NATPool   `json:"nat_pool,omitempty"`
 
+	StaticNAT []StaticNAT `json:"static_nat,omitempty"`
+
+	// Устаревшие поля OSPF, сохранены для чтения старых JSON-моделей.
+	OSPF                []OSPF   `json:"ospf,omitempty"`
 	OSPFRouterID        string   `json:"ospf_router_id,omitempty"`
 	OSPFPassiveDefault  bool     `json:"ospf_passive_default,omitempty"`
 	OSPFNoPassiveIfaces []string `json:"ospf_no_passive_ifaces,omitempty"`
 
-	ACLs    []ACL   `json:"acls,omitempty"`
+	ACLs         []ACL         `json:"acls,omitempty"`
+	ObjectGroups []ObjectGroup `json:"object_groups,omitempty"`
+	TimeRanges   []TimeRange   `json:"time_ranges,omitempty"`
+	Lines        []Line        `json:"lines,omitempty"`
+
+	PrefixLists []PrefixList `json:"prefix_lists,omitempty"`
+	RouteMaps   []RouteMap   `json:"route_maps,omitempty"`
+
+	DHCP    DHCP    `json:"dhcp,omitempty"`
 	Service Service `json:"service,omitempty"`
 	STP     STP     `json:"stp,omitempty"`
 }
@@ -86,3 +139,44 @@ type Service struct {
 type STP struct {
 	Mode string `json:"mode"` // pvst, rstp, mstp
 }
+
+type DHCPLease struct {
+	Days     int  `json:"days,omitempty"`
+	Hours    int  `json:"hours,omitempty"`
+	Minutes  int  `json:"minutes,omitempty"`
+	Infinite bool `json:"infinite,omitempty"`
+}
+
+type DHCPPool struct {
+	Name           string     `json:"name"`
+	Network        string     `json:"network,omitempty"`
+	Mask           string     `json:"mask,omitempty"`
+	DefaultRouters []string   `json:"default_routers,omitempty"`
+	DNSServers     []string   `json:"dns_servers,omitempty"`
+	DomainName     string     `json:"domain_name,omitempty"`
+	Lease          *DHCPLease `json:"lease,omitempty"`
+	// Interface is set for pools bound to an interface (Huawei "dhcp select interface").
+	Interface string `json:"interface,omitempty"`
+}
+
+type DHCPExcluded struct {
+	Start string `json:"start"`
+	End   string `json:"end,omitempty"`
+}
+
+type DHCPRelay struct {
+	Interface string   `json:"interface"`
+	Servers   []string `json:"servers"`
+}
+
+type DHCP struct {
+	Pools    []DHCPPool     `json:"pools,omitempty"`
+	Excluded []DHCPExcluded `json:"excluded,omitempty"`
+	Relays   []DHCPRelay    `json:"relays,omitempty"`
+	// GlobalInterfaces lists interfaces serving addresses from global pools (Huawei "dhcp select global").
+	GlobalInterfaces []string `json:"global_interfaces,omitempty"`
+}
+
+func (d DHCP) Empty() bool {
+	return len(d.Pools) == 0 && len(d.Excluded) == 0 && len(d.Relays) == 0 && len(d.GlobalInterfaces) == 0
+}
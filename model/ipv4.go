@@ -0,0 +1,174 @@
+package model
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ParseIPv4 converts a dotted-quad address into its numeric form.
+func ParseIPv4(s string) (uint32, bool) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) != 4 {
+		return 0, false
+	}
+	var v uint32
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 255 {
+			return 0, false
+		}
+		v = v<<8 | uint32(n)
+	}
+	return v, true
+}
+
+func FormatIPv4(v uint32) string {
+	return fmt.Sprintf("%d.%d.%d.%d", v>>24, (v>>16)&0xff, (v>>8)&0xff, v&0xff)
+}
+
+func IsIPv4(s string) bool {
+	_, ok := ParseIPv4(s)
+	return ok
+}
+
+// MaskFromLength returns the dotted netmask for a prefix length.
+func MaskFromLength(n int) string {
+	if n <= 0 {
+		return "0.0.0.0"
+	}
+	if n > 32 {
+		n = 32
+	}
+	return FormatIPv4(^uint32(0) << (32 - n))
+}
+
+// MaskLength returns the prefix length of a contiguous dotted netmask.
+func MaskLength(mask string) (int, bool) {
+	v, ok := ParseIPv4(mask)
+	if !ok {
+		return 0, false
+	}
+	n := 0
+	for v&0x80000000 != 0 {
+		n++
+		v <<= 1
+	}
+	if v != 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// NormalizeMask accepts a dotted mask, a prefix length or "/len" and returns a dotted mask.
+func NormalizeMask(s string) string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "/")
+	if IsIPv4(s) {
+		return s
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 32 {
+		return MaskFromLength(n)
+	}
+	return s
+}
+
+// WildcardToMask inverts a wildcard into a netmask (and vice versa).
+func WildcardToMask(wildcard string) string {
+	v, ok := ParseIPv4(wildcard)
+	if !ok {
+		return wildcard
+	}
+	return FormatIPv4(^v)
+}
+
+// SubnetContains reports whether addr belongs to network/mask.
+func SubnetContains(network, mask, addr string) bool {
+	n, ok1 := ParseIPv4(network)
+	m, ok2 := ParseIPv4(NormalizeMask(mask))
+	a, ok3 := ParseIPv4(addr)
+	if !ok1 || !ok2 || !ok3 {
+		return false
+	}
+	return n&m == a&m
+}
+
+// SubnetOf returns the network address of addr/mask.
+func SubnetOf(addr, mask string) string {
+	a, ok1 := ParseIPv4(addr)
+	m, ok2 := ParseIPv4(NormalizeMask(mask))
+	if !ok1 || !ok2 {
+		return ""
+	}
+	return FormatIPv4(a & m)
+}
+
+// SplitIPMask splits an interface "addr mask" value.
+func SplitIPMask(ip string) (string, string) {
+	parts := strings.Fields(ip)
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		if slash := strings.Index(parts[0], "/"); slash > 0 {
+			return parts[0][:slash], NormalizeMask(parts[0][slash+1:])
+		}
+		return parts[0], ""
+	default:
+		return parts[0], NormalizeMask(parts[1])
+	}
+}
+
+// CoveringMask returns the shortest netmask whose subnet contains both addresses.
+func CoveringMask(start, end string) string {
+	s, ok1 := ParseIPv4(start)
+	e, ok2 := ParseIPv4(end)
+	if !ok1 || !ok2 {
+		return ""
+	}
+	return MaskFromLength(32 - bits.Len32(s^e))
+}
+
+// WildcardOverlaps reports whether two address/wildcard pairs match at least one common address.
+func WildcardOverlaps(addrA, wildcardA, addrB, wildcardB string) bool {
+	a, ok1 := ParseIPv4(addrA)
+	b, ok2 := ParseIPv4(addrB)
+	if !ok1 || !ok2 {
+		return false
+	}
+	wa, _ := ParseIPv4(wildcardA)
+	wb, _ := ParseIPv4(wildcardB)
+	return (a^b)&^wa&^wb == 0
+}
+
+// WildcardContains reports whether every address matched by B is also matched by A.
+func WildcardContains(addrA, wildcardA, addrB, wildcardB string) bool {
+	a, ok1 := ParseIPv4(addrA)
+	b, ok2 := ParseIPv4(addrB)
+	if !ok1 || !ok2 {
+		return false
+	}
+	wa, _ := ParseIPv4(wildcardA)
+	wb, _ := ParseIPv4(wildcardB)
+	return wb&^wa == 0 && (a^b)&^wa == 0
+}
+
+// RangeBlocks splits an address range into the smallest list of aligned
+// subnets, returned as network/wildcard pairs.
+func RangeBlocks(start, end string) [][2]string {
+	s, ok1 := ParseIPv4(start)
+	e, ok2 := ParseIPv4(end)
+	if !ok1 || !ok2 || s > e {
+		return nil
+	}
+	var blocks [][2]string
+	for cur := uint64(s); cur <= uint64(e); {
+		size := uint64(1)
+		for cur%(size*2) == 0 && cur+size*2-1 <= uint64(e) && size < 1<<32 {
+			size *= 2
+		}
+		blocks = append(blocks, [2]string{FormatIPv4(uint32(cur)), FormatIPv4(uint32(size - 1))})
+		cur += size
+	}
+	return blocks
+}
@@ -0,0 +1,63 @@
+package model
+
+// MigrateLegacy переносит устаревшие поля старых JSON-моделей в актуальную структуру.
+func MigrateLegacy(cfg *Config) {
+	migrateLegacyNAT(cfg)
+	migrateLegacyRoutes(cfg)
+	migrateLegacyOSPF(cfg)
+}
+
+// migrateLegacyOSPF раскладывает плоский список network и общие настройки по процессам,
+// повторяя прежнее поведение генераторов: общий router-id и passive для всех процессов.
+func migrateLegacyOSPF(cfg *Config) {
+	for _, o := range cfg.OSPF {
+		p := cfg.OSPFProcessRef(o.ProcessID)
+		p.Networks = append(p.Networks, OSPFNetwork{Network: o.Network, Wildcard: o.Wildcard, Area: o.Area})
+	}
+	for i := range cfg.OSPFProcesses {
+		p := &cfg.OSPFProcesses[i]
+		if p.RouterID == "" {
+			p.RouterID = cfg.OSPFRouterID
+		}
+		if cfg.OSPFPassiveDefault {
+			p.PassiveDefault = true
+			p.NoPassiveInterfaces = append(p.NoPassiveInterfaces, cfg.OSPFNoPassiveIfaces...)
+		}
+	}
+	cfg.OSPF = nil
+	cfg.OSPFRouterID = ""
+	cfg.OSPFPassiveDefault = false
+	cfg.OSPFNoPassiveIfaces = nil
+}
+
+// migrateLegacyRoutes переносит выходной интерфейс, ранее хранившийся в Gateway.
+func migrateLegacyRoutes(cfg *Config) {
+	for i := range cfg.Routes {
+		r := &cfg.Routes[i]
+		if r.Gateway != "" && r.Interface == "" && !IsIPv4(r.Gateway) {
+			r.Interface = r.Gateway
+			r.Gateway = ""
+		}
+	}
+}
+
+func migrateLegacyNAT(cfg *Config) {
+	for _, pair := range cfg.NAT {
+		setInterfaceNATRole(cfg, pair.Inside, "inside")
+		setInterfaceNATRole(cfg, pair.Outside, "outside")
+	}
+	cfg.NAT = nil
+}
+
+func setInterfaceNATRole(cfg *Config, name, role string) {
+	if name == "" {
+		return
+	}
+	for i := range cfg.Interfaces {
+		if cfg.Interfaces[i].Name == name {
+			cfg.Interfaces[i].NAT = role
+			return
+		}
+	}
+	cfg.Interfaces = append(cfg.Interfaces, Interface{Name: name, NAT: role})
+}
@@ -0,0 +1,40 @@
+package model
+
+// PrefixListEntry is one line of a Cisco "ip prefix-list" or a Huawei "ip ip-prefix".
+type PrefixListEntry struct {
+	Sequence int    `json:"sequence,omitempty"`
+	Action   string `json:"action"`
+	Prefix   string `json:"prefix"`
+	Length   int    `json:"length"`
+	GE       int    `json:"ge,omitempty"`
+	LE       int    `json:"le,omitempty"`
+}
+
+type PrefixList struct {
+	Name    string            `json:"name"`
+	Entries []PrefixListEntry `json:"entries,omitempty"`
+}
+
+// RouteMapEntry is a Cisco "route-map" sequence or a Huawei "route-policy" node.
+// MatchACLs holds ACL references (number or name); Raw keeps clauses without a mapping.
+type RouteMapEntry struct {
+	Sequence int    `json:"sequence"`
+	Action   string `json:"action"`
+
+	MatchPrefixLists []string `json:"match_prefix_lists,omitempty"`
+	MatchACLs        []string `json:"match_acls,omitempty"`
+	MatchTags        []string `json:"match_tags,omitempty"`
+	MatchInterfaces  []string `json:"match_interfaces,omitempty"`
+
+	SetMetric          string `json:"set_metric,omitempty"`
+	SetTag             string `json:"set_tag,omitempty"`
+	SetLocalPreference string `json:"set_local_preference,omitempty"`
+	SetNextHop         string `json:"set_next_hop,omitempty"`
+
+	Raw []string `json:"raw,omitempty"`
+}
+
+type RouteMap struct {
+	Name    string          `json:"name"`
+	Entries []RouteMapEntry `json:"entries,omitempty"`
+}
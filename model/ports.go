@@ -0,0 +1,235 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// servicePort is a well-known TCP/UDP port with the keyword each vendor uses for it.
+// An empty keyword means the vendor only accepts the number.
+type servicePort struct {
+	proto  string
+	port   int
+	cisco  string
+	huawei string
+}
+
+var servicePorts = []servicePort{
+	{"tcp", 7, "echo", "echo"},
+	{"tcp", 9, "discard", "discard"},
+	{"tcp", 13, "daytime", "daytime"},
+	{"tcp", 19, "chargen", "chargen"},
+	{"tcp", 20, "ftp-data", "ftp-data"},
+	{"tcp", 21, "ftp", "ftp"},
+	{"tcp", 23, "telnet", "telnet"},
+	{"tcp", 25, "smtp", "smtp"},
+	{"tcp", 37, "time", "time"},
+	{"tcp", 43, "whois", "whois"},
+	{"tcp", 49, "tacacs", "tacacs"},
+	{"tcp", 53, "domain", "domain"},
+	{"tcp", 70, "gopher", "gopher"},
+	{"tcp", 79, "finger", "finger"},
+	{"tcp", 80, "www", "www"},
+	{"tcp", 101, "hostname", "hostname"},
+	{"tcp", 109, "pop2", "pop2"},
+	{"tcp", 110, "pop3", "pop3"},
+	{"tcp", 111, "sunrpc", "sunrpc"},
+	{"tcp", 113, "ident", ""},
+	{"tcp", 119, "nntp", "nntp"},
+	{"tcp", 135, "msrpc", ""},
+	{"tcp", 179, "bgp", "bgp"},
+	{"tcp", 194, "irc", "irc"},
+	{"tcp", 496, "pim-auto-rp", ""},
+	{"tcp", 512, "exec", "exec"},
+	{"tcp", 513, "login", "login"},
+	{"tcp", 514, "cmd", "cmd"},
+	{"tcp", 515, "lpd", "lpd"},
+	{"tcp", 517, "talk", "talk"},
+	{"tcp", 540, "uucp", "uucp"},
+	{"tcp", 543, "klogin", "klogin"},
+	{"tcp", 544, "kshell", "kshell"},
+	{"tcp", 3949, "drip", ""},
+	{"tcp", 15001, "onep-plain", ""},
+	{"tcp", 15002, "onep-tls", ""},
+
+	{"udp", 7, "echo", "echo"},
+	{"udp", 9, "discard", "discard"},
+	{"udp", 37, "time", "time"},
+	{"udp", 42, "nameserver", "nameserver"},
+	{"udp", 49, "tacacs", ""},
+	{"udp", 53, "domain", "dns"},
+	{"udp", 65, "", "tacacs-ds"},
+	{"udp", 67, "bootps", "bootps"},
+	{"udp", 68, "bootpc", "bootpc"},
+	{"udp", 69, "tftp", "tftp"},
+	{"udp", 90, "", "dnsix"},
+	{"udp", 111, "sunrpc", "sunrpc"},
+	{"udp", 123, "ntp", "ntp"},
+	{"udp", 137, "netbios-ns", "netbios-ns"},
+	{"udp", 138, "netbios-dgm", "netbios-dgm"},
+	{"udp", 139, "netbios-ss", "netbios-ssn"},
+	{"udp", 161, "snmp", "snmp"},
+	{"udp", 162, "snmptrap", "snmptrap"},
+	{"udp", 177, "xdmcp", "xdmcp"},
+	{"udp", 195, "dnsix", ""},
+	{"udp", 434, "mobile-ip", "mobilip-ag"},
+	{"udp", 435, "", "mobilip-mn"},
+	{"udp", 496, "pim-auto-rp", ""},
+	{"udp", 500, "isakmp", ""},
+	{"udp", 512, "biff", "biff"},
+	{"udp", 513, "who", "who"},
+	{"udp", 514, "syslog", "syslog"},
+	{"udp", 517, "talk", "talk"},
+	{"udp", 520, "rip", "rip"},
+	{"udp", 521, "ripng", ""},
+	{"udp", 4500, "non500-isakmp", ""},
+}
+
+// ipProtocol is an IP protocol with the canonical name used in the model and vendor keywords.
+type ipProtocol struct {
+	number int
+	name   string
+	cisco  string
+	huawei string
+}
+
+var ipProtocols = []ipProtocol{
+	{1, "icmp", "icmp", "icmp"},
+	{2, "igmp", "igmp", "igmp"},
+	{4, "ipinip", "ipinip", "ipinip"},
+	{6, "tcp", "tcp", "tcp"},
+	{17, "udp", "udp", "udp"},
+	{47, "gre", "gre", "gre"},
+	{50, "esp", "esp", ""},
+	{51, "ah", "ahp", ""},
+	{88, "eigrp", "eigrp", ""},
+	{89, "ospf", "ospf", "ospf"},
+	{94, "nos", "nos", ""},
+	{103, "pim", "pim", ""},
+	{108, "pcp", "pcp", ""},
+}
+
+// PortNumber resolves a port given as a number or as a keyword. Keywords of the
+// vendor ("cisco" or "huawei") win over the other vendor's: some names mean
+// different ports (udp "dnsix" is 195 on Cisco and 90 on Huawei). An empty vendor
+// accepts either table. Protocol "tcp-udp" (Cisco object-groups) matches both protocols.
+func PortNumber(vendor, proto, name string) (int, bool) {
+	if n, err := strconv.Atoi(name); err == nil {
+		return n, n >= 0 && n <= 65535
+	}
+	name = strings.ToLower(name)
+	fallback, found := 0, false
+	for _, p := range servicePorts {
+		if !strings.Contains(proto, p.proto) {
+			continue
+		}
+		own, other := p.cisco, p.huawei
+		if vendor == "huawei" {
+			own, other = p.huawei, p.cisco
+		}
+		if own == name {
+			return p.port, true
+		}
+		if other == name && !found {
+			fallback, found = p.port, true
+		}
+	}
+	return fallback, found
+}
+
+// PortKeyword returns the keyword the vendor ("cisco" or "huawei") uses for the port,
+// or the number when the vendor has no keyword for it.
+func PortKeyword(vendor, proto string, port int) string {
+	for _, p := range servicePorts {
+		if p.port != port || !strings.Contains(proto, p.proto) {
+			continue
+		}
+		if vendor == "huawei" && p.huawei != "" {
+			return p.huawei
+		}
+		if vendor == "cisco" && p.cisco != "" {
+			return p.cisco
+		}
+	}
+	return strconv.Itoa(port)
+}
+
+// CanonicalPortSpec converts the port values of "eq www" / "range ftp-data ftp",
+// written with the vendor's keywords, to numbers. Unknown keywords are kept as they are.
+func CanonicalPortSpec(vendor, proto, spec string) string {
+	return mapPortSpec(spec, func(v string) string {
+		if n, ok := PortNumber(vendor, proto, v); ok {
+			return strconv.Itoa(n)
+		}
+		return v
+	})
+}
+
+// VendorPortSpec renders a canonical port spec with the vendor's keywords.
+func VendorPortSpec(vendor, proto, spec string) string {
+	return mapPortSpec(spec, func(v string) string {
+		if n, ok := PortNumber("", proto, v); ok {
+			return PortKeyword(vendor, proto, n)
+		}
+		return v
+	})
+}
+
+func mapPortSpec(spec string, fn func(string) string) string {
+	parts := strings.Fields(spec)
+	for i := 1; i < len(parts); i++ {
+		parts[i] = fn(parts[i])
+	}
+	return strings.Join(parts, " ")
+}
+
+// ProtocolNumber resolves an IP protocol given as a number, a canonical name or a vendor keyword.
+func ProtocolNumber(name string) (int, bool) {
+	if n, err := strconv.Atoi(name); err == nil {
+		return n, n >= 0 && n <= 255
+	}
+	name = strings.ToLower(name)
+	for _, p := range ipProtocols {
+		if p.name == name || p.cisco == name || p.huawei == name {
+			return p.number, true
+		}
+	}
+	return 0, false
+}
+
+// CanonicalProtocol returns the model name of an ACL protocol ("ip" stays "ip").
+// Numbers without a name and unknown keywords are returned unchanged.
+func CanonicalProtocol(name string) string {
+	name = strings.ToLower(name)
+	if name == "ip" {
+		return name
+	}
+	if n, ok := ProtocolNumber(name); ok {
+		for _, p := range ipProtocols {
+			if p.number == n {
+				return p.name
+			}
+		}
+	}
+	return name
+}
+
+// ProtocolKeyword returns the vendor keyword for a canonical protocol, or its number.
+func ProtocolKeyword(vendor, proto string) string {
+	if proto == "ip" {
+		return proto
+	}
+	for _, p := range ipProtocols {
+		if p.name != proto {
+			continue
+		}
+		if vendor == "huawei" && p.huawei != "" {
+			return p.huawei
+		}
+		if vendor == "cisco" && p.cisco != "" {
+			return p.cisco
+		}
+		return strconv.Itoa(p.number)
+	}
+	return proto
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestServicePortsRoundTrip(t *testing.T) {
+	for _, p := range servicePorts {
+		for _, vendor := range []string{"cisco", "huawei"} {
+			keyword := p.cisco
+			if vendor == "huawei" {
+				keyword = p.huawei
+			}
+			if keyword == "" {
+				keyword = strconv.Itoa(p.port)
+			}
+			number := strconv.Itoa(p.port)
+			t.Run(vendor+"/"+p.proto+"/"+keyword, func(t *testing.T) {
+				if n, ok := PortNumber(vendor, p.proto, keyword); !ok || n != p.port {
+					t.Errorf("PortNumber(%q) = %d, %t, want %d", keyword, n, ok, p.port)
+				}
+				if got := PortKeyword(vendor, p.proto, p.port); got != keyword {
+					t.Errorf("PortKeyword(%d) = %q, want %q", p.port, got, keyword)
+				}
+				if got, want := CanonicalPortSpec(vendor, p.proto, "eq "+keyword), "eq "+number; got != want {
+					t.Errorf("CanonicalPortSpec = %q, want %q", got, want)
+				}
+				if got, want := CanonicalPortSpec(vendor, p.proto, "range "+keyword+" "+keyword), "range "+number+" "+number; got != want {
+					t.Errorf("CanonicalPortSpec = %q, want %q", got, want)
+				}
+				if got, want := VendorPortSpec(vendor, p.proto, "eq "+number), "eq "+keyword; got != want {
+					t.Errorf("VendorPortSpec = %q, want %q", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestIPProtocolsRoundTrip(t *testing.T) {
+	for _, p := range ipProtocols {
+		for _, vendor := range []string{"cisco", "huawei"} {
+			keyword := p.cisco
+			if vendor == "huawei" {
+				keyword = p.huawei
+			}
+			if keyword == "" {
+				keyword = strconv.Itoa(p.number)
+			}
+			t.Run(vendor+"/"+keyword, func(t *testing.T) {
+				if n, ok := ProtocolNumber(keyword); !ok || n != p.number {
+					t.Errorf("ProtocolNumber(%q) = %d, %t, want %d", keyword, n, ok, p.number)
+				}
+				if got := CanonicalProtocol(keyword); got != p.name {
+					t.Errorf("CanonicalProtocol(%q) = %q, want %q", keyword, got, p.name)
+				}
+				if got := ProtocolKeyword(vendor, p.name); got != keyword {
+					t.Errorf("ProtocolKeyword(%q) = %q, want %q", p.name, got, keyword)
+				}
+			})
+		}
+	}
+}
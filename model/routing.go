@@ -0,0 +1,215 @@
+package model
+
+// Redistribution is a Cisco "redistribute" or a Huawei "import-route" statement.
+// Protocol uses Cisco names (static, connected, ospf, rip, bgp, eigrp, isis);
+// Process holds the source process ID, AS number or IS-IS tag when relevant.
+type Redistribution struct {
+	Protocol   string `json:"protocol"`
+	Process    string `json:"process,omitempty"`
+	Metric     int    `json:"metric,omitempty"`
+	MetricType int    `json:"metric_type,omitempty"`
+	Tag        int    `json:"tag,omitempty"`
+	RouteMap   string `json:"route_map,omitempty"`
+	// EIGRPMetric keeps the composite EIGRP metric ("bw delay reliability load mtu").
+	EIGRPMetric string `json:"eigrp_metric,omitempty"`
+}
+
+// DefaultOriginate is "default-information originate" / "default-route-advertise".
+type DefaultOriginate struct {
+	Always     bool   `json:"always,omitempty"`
+	Metric     int    `json:"metric,omitempty"`
+	MetricType int    `json:"metric_type,omitempty"`
+	RouteMap   string `json:"route_map,omitempty"`
+}
+
+type OSPFNetwork struct {
+	Network  string `json:"network"`
+	Wildcard string `json:"wildcard"`
+	Area     string `json:"area"`
+}
+
+// OSPFAreaRange is a Cisco "area range" or a Huawei "abr-summary".
+type OSPFAreaRange struct {
+	Network      string `json:"network"`
+	Mask         string `json:"mask"`
+	NotAdvertise bool   `json:"not_advertise,omitempty"`
+	Cost         int    `json:"cost,omitempty"`
+}
+
+type OSPFArea struct {
+	ID string `json:"id"`
+	// Type is empty for a normal area or one of stub, totally-stubby, nssa, totally-nssa.
+	Type                 string          `json:"type,omitempty"`
+	NSSADefaultOriginate bool            `json:"nssa_default_originate,omitempty"`
+	DefaultCost          int             `json:"default_cost,omitempty"`
+	Ranges               []OSPFAreaRange `json:"ranges,omitempty"`
+}
+
+// SPFTimers are SPF throttle intervals in milliseconds.
+type SPFTimers struct {
+	Start int `json:"start"`
+	Hold  int `json:"hold"`
+	Max   int `json:"max"`
+}
+
+type OSPFProcess struct {
+	ProcessID           int               `json:"process_id"`
+	RouterID            string            `json:"router_id,omitempty"`
+	PassiveDefault      bool              `json:"passive_default,omitempty"`
+	PassiveInterfaces   []string          `json:"passive_interfaces,omitempty"`
+	NoPassiveInterfaces []string          `json:"no_passive_interfaces,omitempty"`
+	Networks            []OSPFNetwork     `json:"networks,omitempty"`
+	Areas               []OSPFArea        `json:"areas,omitempty"`
+	DefaultInformation  *DefaultOriginate `json:"default_information,omitempty"`
+	Redistribute        []Redistribution  `json:"redistribute,omitempty"`
+	// ReferenceBandwidth is in Mbit/s.
+	ReferenceBandwidth int        `json:"reference_bandwidth,omitempty"`
+	SPFTimers          *SPFTimers `json:"spf_timers,omitempty"`
+}
+
+// InterfaceOSPF holds per-interface OSPF settings.
+type InterfaceOSPF struct {
+	// ProcessID and Area are set for interface-based enablement ("ip ospf <pid> area" / "ospf enable").
+	ProcessID int    `json:"process_id,omitempty"`
+	Area      string `json:"area,omitempty"`
+	Cost      int    `json:"cost,omitempty"`
+	// NetworkType is one of point-to-point, broadcast, non-broadcast, point-to-multipoint.
+	NetworkType   string `json:"network_type,omitempty"`
+	Priority      *int   `json:"priority,omitempty"`
+	HelloInterval int    `json:"hello_interval,omitempty"`
+	DeadInterval  int    `json:"dead_interval,omitempty"`
+	// AuthMode is "simple", "md5" or "key-chain".
+	AuthMode  string `json:"auth_mode,omitempty"`
+	AuthKeyID int    `json:"auth_key_id,omitempty"`
+	AuthKey   string `json:"auth_key,omitempty"`
+	// AuthKeyEncrypted is set when the key could only be read in the vendor's encrypted form.
+	AuthKeyEncrypted bool   `json:"auth_key_encrypted,omitempty"`
+	KeyChain         string `json:"key_chain,omitempty"`
+}
+
+// AreaIDs returns the areas of the process in order of first appearance.
+func (p OSPFProcess) AreaIDs() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, n := range p.Networks {
+		add(n.Area)
+	}
+	for _, a := range p.Areas {
+		add(a.ID)
+	}
+	return ids
+}
+
+func (p OSPFProcess) Area(id string) (OSPFArea, bool) {
+	for _, a := range p.Areas {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return OSPFArea{}, false
+}
+
+func (p *OSPFProcess) AreaRef(id string) *OSPFArea {
+	for i := range p.Areas {
+		if p.Areas[i].ID == id {
+			return &p.Areas[i]
+		}
+	}
+	p.Areas = append(p.Areas, OSPFArea{ID: id})
+	return &p.Areas[len(p.Areas)-1]
+}
+
+func (c *Config) OSPFProcessRef(pid int) *OSPFProcess {
+	for i := range c.OSPFProcesses {
+		if c.OSPFProcesses[i].ProcessID == pid {
+			return &c.OSPFProcesses[i]
+		}
+	}
+	c.OSPFProcesses = append(c.OSPFProcesses, OSPFProcess{ProcessID: pid})
+	return &c.OSPFProcesses[len(c.OSPFProcesses)-1]
+}
+
+type RIPProcess struct {
+	ProcessID           int               `json:"process_id,omitempty"`
+	Version             int               `json:"version,omitempty"`
+	Networks            []string          `json:"networks,omitempty"`
+	PassiveDefault      bool              `json:"passive_default,omitempty"`
+	PassiveInterfaces   []string          `json:"passive_interfaces,omitempty"`
+	NoPassiveInterfaces []string          `json:"no_passive_interfaces,omitempty"`
+	NoAutoSummary       bool              `json:"no_auto_summary,omitempty"`
+	Redistribute        []Redistribution  `json:"redistribute,omitempty"`
+	DefaultInformation  *DefaultOriginate `json:"default_information,omitempty"`
+}
+
+type EIGRPNetwork struct {
+	Network  string `json:"network"`
+	Wildcard string `json:"wildcard,omitempty"`
+}
+
+type EIGRPProcess struct {
+	AS                  int              `json:"as"`
+	RouterID            string           `json:"router_id,omitempty"`
+	Networks            []EIGRPNetwork   `json:"networks,omitempty"`
+	PassiveDefault      bool             `json:"passive_default,omitempty"`
+	PassiveInterfaces   []string         `json:"passive_interfaces,omitempty"`
+	NoPassiveInterfaces []string         `json:"no_passive_interfaces,omitempty"`
+	NoAutoSummary       bool             `json:"no_auto_summary,omitempty"`
+	Redistribute        []Redistribution `json:"redistribute,omitempty"`
+}
+
+func (c *Config) RIPProcessRef(pid int) *RIPProcess {
+	for i := range c.RIP {
+		if c.RIP[i].ProcessID == pid {
+			return &c.RIP[i]
+		}
+	}
+	c.RIP = append(c.RIP, RIPProcess{ProcessID: pid})
+	return &c.RIP[len(c.RIP)-1]
+}
+
+func (c *Config) EIGRPProcessRef(as int) *EIGRPProcess {
+	for i := range c.EIGRP {
+		if c.EIGRP[i].AS == as {
+			return &c.EIGRP[i]
+		}
+	}
+	c.EIGRP = append(c.EIGRP, EIGRPProcess{AS: as})
+	return &c.EIGRP[len(c.EIGRP)-1]
+}
+
+// ISISProcess is a Cisco "router isis [tag]" or a Huawei "isis <id>".
+// Levels are level-1, level-2 or level-1-2.
+type ISISProcess struct {
+	Tag   string   `json:"tag,omitempty"`
+	NET   []string `json:"net,omitempty"`
+	Level string   `json:"level,omitempty"`
+	// MetricStyle is narrow, wide or transition.
+	MetricStyle        string            `json:"metric_style,omitempty"`
+	PassiveInterfaces  []string          `json:"passive_interfaces,omitempty"`
+	Redistribute       []Redistribution  `json:"redistribute,omitempty"`
+	DefaultInformation *DefaultOriginate `json:"default_information,omitempty"`
+}
+
+// InterfaceISIS holds "ip router isis" / "isis enable" and per-interface settings.
+type InterfaceISIS struct {
+	Tag          string `json:"tag,omitempty"`
+	CircuitType  string `json:"circuit_type,omitempty"`
+	Metric       int    `json:"metric,omitempty"`
+	PointToPoint bool   `json:"point_to_point,omitempty"`
+}
+
+func (c *Config) ISISProcessRef(tag string) *ISISProcess {
+	for i := range c.ISIS {
+		if c.ISIS[i].Tag == tag {
+			return &c.ISIS[i]
+		}
+	}
+	c.ISIS = append(c.ISIS, ISISProcess{Tag: tag})
+	return &c.ISIS[len(c.ISIS)-1]
+}
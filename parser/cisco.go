@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"converter/model"
@@ -22,8 +23,54 @@ func ParseCisco(path string) (*model.Config, error) {
 	var currentInterface *model.Interface
 	var currentVlan *model.Vlan
 	var currentOSPF int
-	var natInside []string
-	var natOutside []string
+	var inRIP bool
+	var currentEIGRP int
+	var inISIS bool
+	var currentISISTag string
+	var currentACL *model.ACL
+	var currentLine *model.Line
+	var currentTimeRange string
+	var currentGroup *model.ObjectGroup
+	var currentGroupProto string
+	var currentDHCPPool *model.DHCPPool
+	var currentRouteMap string
+	var currentRouteMapEntry *model.RouteMapEntry
+	// ospfAreaAuth хранит "area N authentication [message-digest]" по процессу и зоне.
+	ospfAreaAuth := make(map[int]map[string]string)
+
+	// closeBlocks завершает все открытые блоки перед началом нового.
+	closeBlocks := func() {
+		if currentInterface != nil {
+			cfg.Interfaces = append(cfg.Interfaces, *currentInterface)
+			currentInterface = nil
+		}
+		if currentVlan != nil {
+			cfg.Vlans = append(cfg.Vlans, *currentVlan)
+			currentVlan = nil
+		}
+		if currentDHCPPool != nil {
+			cfg.DHCP.Pools = append(cfg.DHCP.Pools, *currentDHCPPool)
+			currentDHCPPool = nil
+		}
+		if currentRouteMapEntry != nil {
+			addRouteMapEntry(cfg, currentRouteMap, *currentRouteMapEntry)
+			currentRouteMapEntry = nil
+		}
+		if currentLine != nil {
+			cfg.Lines = append(cfg.Lines, *currentLine)
+			currentLine = nil
+		}
+		currentOSPF = 0
+		inRIP = false
+		currentEIGRP = 0
+		inISIS = false
+		currentACL = nil
+		currentTimeRange = ""
+		if currentGroup != nil {
+			cfg.ObjectGroups = append(cfg.ObjectGroups, *currentGroup)
+			currentGroup = nil
+		}
+	}
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -34,9 +81,7 @@ func ParseCisco(path string) (*model.Config, error) {
 		switch {
 		// VLAN
 		case strings.HasPrefix(line, "vlan "):
-			if currentVlan != nil {
-				cfg.Vlans = append(cfg.Vlans, *currentVlan)
-			}
+			closeBlocks()
 			var id int
 			fmt.Sscanf(line, "vlan %d", &id)
 			currentVlan = &model.Vlan{ID: id}
@@ -50,9 +95,7 @@ func ParseCisco(path string) (*model.Config, error) {
 
 		// Интерфейсы
 		case strings.HasPrefix(line, "interface "):
-			if currentInterface != nil {
-				cfg.Interfaces = append(cfg.Interfaces, *currentInterface)
-			}
+			closeBlocks()
 			name := strings.TrimPrefix(line, "interface ")
 			currentInterface = &model.Interface{Name: name}
 
@@ -74,11 +117,43 @@ func ParseCisco(path string) (*model.Config, error) {
 				currentInterface.IP = parts[2] + " " + parts[3]
 			}
 
+		case strings.HasPrefix(line, "ip helper-address ") && currentInterface != nil:
+			addDHCPRelayServer(cfg, currentInterface.Name, strings.TrimPrefix(line, "ip helper-address "))
+
+		case strings.HasPrefix(line, "bandwidth ") && currentInterface != nil:
+			fmt.Sscanf(line, "bandwidth %d", &currentInterface.Bandwidth)
+
+		case strings.HasPrefix(line, "ip ospf ") && currentInterface != nil:
+			if currentInterface.OSPF == nil {
+				currentInterface.OSPF = &model.InterfaceOSPF{}
+			}
+			parseCiscoInterfaceOSPF(currentInterface.OSPF, strings.Fields(line)[2:])
+
+		case (line == "ip router isis" || strings.HasPrefix(line, "ip router isis ")) && currentInterface != nil:
+			if currentInterface.ISIS == nil {
+				currentInterface.ISIS = &model.InterfaceISIS{}
+			}
+			currentInterface.ISIS.Tag = strings.TrimSpace(strings.TrimPrefix(line, "ip router isis"))
+
+		case strings.HasPrefix(line, "isis ") && currentInterface != nil:
+			if currentInterface.ISIS == nil {
+				currentInterface.ISIS = &model.InterfaceISIS{}
+			}
+			parseCiscoInterfaceISIS(currentInterface.ISIS, strings.Fields(line)[1:])
+
+		case strings.HasPrefix(line, "ip access-group ") && currentInterface != nil:
+			parts := strings.Fields(line)
+			if len(parts) == 4 && parts[3] == "in" {
+				currentInterface.ACLIn = parts[2]
+			} else if len(parts) == 4 && parts[3] == "out" {
+				currentInterface.ACLOut = parts[2]
+			}
+
 		case line == "ip nat inside" && currentInterface != nil:
-			natInside = append(natInside, currentInterface.Name)
+			currentInterface.NAT = "inside"
 
 		case line == "ip nat outside" && currentInterface != nil:
-			natOutside = append(natOutside, currentInterface.Name)
+			currentInterface.NAT = "outside"
 
 		case line == "exit" && currentInterface != nil:
 			cfg.Interfaces = append(cfg.Interfaces, *currentInterface)
@@ -88,34 +163,130 @@ func ParseCisco(path string) (*model.Config, error) {
 			currentInterface.TrunkVlans = strings.TrimPrefix(line, "switchport trunk allowed vlan ")
 
 		case strings.HasPrefix(line, "router ospf "):
+			closeBlocks()
 			var id int
 			fmt.Sscanf(line, "router ospf %d", &id)
 			currentOSPF = id
+			cfg.OSPFProcessRef(id)
 
-		case strings.HasPrefix(line, "router-id ") && currentOSPF != 0:
-			cfg.OSPFRouterID = strings.TrimPrefix(line, "router-id ")
+		case line == "exit" && currentOSPF != 0:
+			currentOSPF = 0
 
-		case line == "passive-interface default" && currentOSPF != 0:
-			cfg.OSPFPassiveDefault = true
+		case currentOSPF != 0 && strings.HasPrefix(line, "area ") && strings.Contains(line, " authentication"):
+			parts := strings.Fields(line)
+			if len(parts) >= 3 && parts[2] == "authentication" {
+				if ospfAreaAuth[currentOSPF] == nil {
+					ospfAreaAuth[currentOSPF] = make(map[string]string)
+				}
+				ospfAreaAuth[currentOSPF][parts[1]] = "simple"
+				if hasToken(parts, "message-digest") {
+					ospfAreaAuth[currentOSPF][parts[1]] = "md5"
+				}
+			}
 
-		case strings.HasPrefix(line, "no passive-interface ") && currentOSPF != 0:
-			iface := strings.TrimPrefix(line, "no passive-interface ")
-			cfg.OSPFNoPassiveIfaces = append(cfg.OSPFNoPassiveIfaces, iface)
+		case currentOSPF != 0 && parseCiscoOSPFLine(cfg.OSPFProcessRef(currentOSPF), line):
 
-		case line == "exit" && currentOSPF != 0:
-			currentOSPF = 0
+		// RIP и EIGRP
+		case line == "router rip":
+			closeBlocks()
+			inRIP = true
+			cfg.RIPProcessRef(1)
+
+		case line == "exit" && inRIP:
+			inRIP = false
+
+		case inRIP && parseCiscoRIPLine(cfg.RIPProcessRef(1), line):
+
+		case strings.HasPrefix(line, "router eigrp "):
+			closeBlocks()
+			var as int
+			fmt.Sscanf(line, "router eigrp %d", &as)
+			if as != 0 {
+				currentEIGRP = as
+				cfg.EIGRPProcessRef(as)
+			}
+
+		case line == "exit" && currentEIGRP != 0:
+			currentEIGRP = 0
+
+		case currentEIGRP != 0 && parseCiscoEIGRPLine(cfg.EIGRPProcessRef(currentEIGRP), line):
+
+		// IS-IS
+		case line == "router isis" || strings.HasPrefix(line, "router isis "):
+			closeBlocks()
+			inISIS = true
+			currentISISTag = strings.TrimSpace(strings.TrimPrefix(line, "router isis"))
+			cfg.ISISProcessRef(currentISISTag)
+
+		case line == "exit" && inISIS:
+			inISIS = false
+
+		case inISIS && parseCiscoISISLine(cfg.ISISProcessRef(currentISISTag), line):
+
+		// DHCP
+		case strings.HasPrefix(line, "ip dhcp pool "):
+			closeBlocks()
+			currentDHCPPool = &model.DHCPPool{Name: strings.TrimPrefix(line, "ip dhcp pool ")}
+
+		case strings.HasPrefix(line, "ip dhcp excluded-address "):
+			parts := strings.Fields(line)
+			if len(parts) >= 4 {
+				excluded := model.DHCPExcluded{Start: parts[3]}
+				if len(parts) >= 5 {
+					excluded.End = parts[4]
+				}
+				cfg.DHCP.Excluded = append(cfg.DHCP.Excluded, excluded)
+			}
 
-		case strings.HasPrefix(line, "network "):
+		case strings.HasPrefix(line, "network ") && currentDHCPPool != nil:
 			parts := strings.Fields(line)
-			if len(parts) >= 5 {
-				cfg.OSPF = append(cfg.OSPF, model.OSPF{
-					ProcessID: currentOSPF,
-					Network:   parts[1],
-					Wildcard:  parts[2],
-					Area:      parts[4],
-				})
+			if len(parts) >= 2 {
+				network, mask := model.SplitIPMask(strings.Join(parts[1:], " "))
+				currentDHCPPool.Network = network
+				currentDHCPPool.Mask = mask
 			}
 
+		case strings.HasPrefix(line, "default-router ") && currentDHCPPool != nil:
+			currentDHCPPool.DefaultRouters = strings.Fields(strings.TrimPrefix(line, "default-router "))
+
+		case strings.HasPrefix(line, "dns-server ") && currentDHCPPool != nil:
+			currentDHCPPool.DNSServers = strings.Fields(strings.TrimPrefix(line, "dns-server "))
+
+		case strings.HasPrefix(line, "domain-name ") && currentDHCPPool != nil:
+			currentDHCPPool.DomainName = strings.TrimPrefix(line, "domain-name ")
+
+		case strings.HasPrefix(line, "lease ") && currentDHCPPool != nil:
+			currentDHCPPool.Lease = parseCiscoDHCPLease(strings.Fields(line)[1:])
+
+		case line == "exit" && currentDHCPPool != nil:
+			closeBlocks()
+
+		// Политики маршрутизации
+		case strings.HasPrefix(line, "ip prefix-list "):
+			if name, entry, ok := parseCiscoPrefixListLine(strings.Fields(line)[2:]); ok {
+				addPrefixListEntry(cfg, name, entry)
+			}
+
+		case strings.HasPrefix(line, "route-map "):
+			closeBlocks()
+			parts := strings.Fields(line)
+			if len(parts) >= 2 {
+				currentRouteMap = parts[1]
+				currentRouteMapEntry = &model.RouteMapEntry{Action: "permit", Sequence: 10}
+				if len(parts) >= 3 {
+					currentRouteMapEntry.Action = strings.ToLower(parts[2])
+				}
+				if len(parts) >= 4 {
+					fmt.Sscanf(parts[3], "%d", &currentRouteMapEntry.Sequence)
+				}
+			}
+
+		case (strings.HasPrefix(line, "match ") || strings.HasPrefix(line, "set ")) && currentRouteMapEntry != nil:
+			parseCiscoRouteMapClause(currentRouteMapEntry, line)
+
+		case line == "exit" && currentRouteMapEntry != nil:
+			closeBlocks()
+
 		case strings.HasPrefix(line, "spanning-tree mode "):
 			cfg.STP.Mode = strings.TrimPrefix(line, "spanning-tree mode ")
 
@@ -126,36 +297,91 @@ func ParseCisco(path string) (*model.Config, error) {
 			cfg.Service.FTP = true
 
 		// Маршруты
+		case strings.HasPrefix(line, "ip route vrf "):
+			cfg.VRFRoutes = append(cfg.VRFRoutes, line)
+
 		case strings.HasPrefix(line, "ip route "):
+			if route, ok := parseCiscoRoute(strings.Fields(line)[2:]); ok {
+				cfg.Routes = append(cfg.Routes, route)
+			}
+
+		// Линии vty/con
+		case strings.HasPrefix(line, "line "):
+			closeBlocks()
+			if l, ok := parseLineRange(strings.Fields(line)[1:]); ok {
+				currentLine = &l
+			}
+
+		case strings.HasPrefix(line, "access-class ") && currentLine != nil:
+			parts := strings.Fields(line)
+			if len(parts) >= 3 && parts[2] == "in" {
+				currentLine.ACLIn = parts[1]
+			} else if len(parts) >= 3 && parts[2] == "out" {
+				currentLine.ACLOut = parts[1]
+			}
+
+		case line == "exit" && currentLine != nil:
+			closeBlocks()
+
+		// Object-groups
+		case strings.HasPrefix(line, "object-group network ") || strings.HasPrefix(line, "object-group service "):
+			closeBlocks()
+			parts := strings.Fields(line)
+			if len(parts) >= 3 {
+				currentGroup = &model.ObjectGroup{Name: parts[2], Type: parts[1]}
+				currentGroupProto = ""
+				if len(parts) >= 4 {
+					currentGroupProto = model.CanonicalProtocol(parts[3])
+				}
+			}
+
+		case line == "exit" && currentGroup != nil:
+			closeBlocks()
+
+		case currentGroup != nil && parseCiscoObjectGroupLine(currentGroup, currentGroupProto, strings.Fields(line)):
+
+		case strings.HasPrefix(line, "time-range "):
+			closeBlocks()
+			currentTimeRange = strings.TrimPrefix(line, "time-range ")
+			cfg.TimeRangeRef(currentTimeRange)
+
+		case line == "exit" && currentTimeRange != "":
+			currentTimeRange = ""
+
+		case currentTimeRange != "" && (strings.HasPrefix(line, "periodic ") || strings.HasPrefix(line, "absolute ")):
+			parseCiscoTimeRangeLine(cfg.TimeRangeRef(currentTimeRange), line)
+
+		case strings.HasPrefix(line, "ip access-list standard ") || strings.HasPrefix(line, "ip access-list extended "):
+			closeBlocks()
 			parts := strings.Fields(line)
-			if len(parts) >= 5 {
-				cfg.Routes = append(cfg.Routes, model.Route{
-					Destination: parts[2],
-					Mask:        parts[3],
-					Gateway:     parts[4],
-				})
+			if len(parts) == 4 {
+				currentACL = getOrCreateNamedACL(cfg, parts[3], parts[2])
 			}
 
+		case line == "exit" && currentACL != nil:
+			currentACL = nil
+
+		case currentACL != nil && parseCiscoNamedACLEntry(currentACL, strings.Fields(line)):
+
 		case strings.HasPrefix(line, "access-list "):
 			if aclID, aclType, rule, ok := parseCiscoACLLine(line); ok {
 				acl := getOrCreateACL(cfg, aclID, aclType)
 				acl.Rules = append(acl.Rules, rule)
 			}
 
+		case strings.HasPrefix(line, "ip nat inside source static "):
+			if nat, ok := parseCiscoStaticNAT(strings.Fields(line)[5:]); ok {
+				cfg.StaticNAT = append(cfg.StaticNAT, nat)
+			}
+
 		case strings.HasPrefix(line, "ip nat inside source list "):
-			var aclID int
-			var outside string
-			if _, err := fmt.Sscanf(line, "ip nat inside source list %d interface %s overload", &aclID, &outside); err == nil {
-				cfg.NATRule = append(cfg.NATRule, model.NATPolicy{
-					ACLID:    aclID,
-					Outside:  outside,
-					Overload: true,
-				})
-			} else if _, err := fmt.Sscanf(line, "ip nat inside source list %d interface %s", &aclID, &outside); err == nil {
-				cfg.NATRule = append(cfg.NATRule, model.NATPolicy{
-					ACLID:   aclID,
-					Outside: outside,
-				})
+			if policy, ok := parseCiscoNATPolicy(strings.Fields(line)[5:]); ok {
+				cfg.NATRule = append(cfg.NATRule, policy)
+			}
+
+		case strings.HasPrefix(line, "ip nat pool "):
+			if pool, ok := parseCiscoNATPool(strings.Fields(line)[3:]); ok {
+				cfg.NATPool = append(cfg.NATPool, pool)
 			}
 		}
 	}
@@ -165,22 +391,654 @@ func ParseCisco(path string) (*model.Config, error) {
 	}
 
 	// финализируем незакрытые блоки
-	if currentInterface != nil {
-		cfg.Interfaces = append(cfg.Interfaces, *currentInterface)
+	closeBlocks()
+	applyCiscoOSPFAreaAuth(cfg, ospfAreaAuth)
+
+	return cfg, nil
+}
+
+// parseCiscoInterfaceOSPF разбирает хвост команд "ip ospf" интерфейса.
+func parseCiscoInterfaceOSPF(o *model.InterfaceOSPF, tokens []string) {
+	if len(tokens) < 1 {
+		return
+	}
+	switch tokens[0] {
+	case "cost":
+		if len(tokens) >= 2 {
+			fmt.Sscanf(tokens[1], "%d", &o.Cost)
+		}
+	case "network":
+		if len(tokens) >= 2 {
+			o.NetworkType = tokens[1]
+		}
+	case "priority":
+		if len(tokens) >= 2 {
+			var prio int
+			fmt.Sscanf(tokens[1], "%d", &prio)
+			o.Priority = &prio
+		}
+	case "hello-interval":
+		if len(tokens) >= 2 {
+			fmt.Sscanf(tokens[1], "%d", &o.HelloInterval)
+		}
+	case "dead-interval":
+		if len(tokens) >= 2 {
+			fmt.Sscanf(tokens[1], "%d", &o.DeadInterval)
+		}
+	case "authentication":
+		switch {
+		case len(tokens) == 1:
+			o.AuthMode = "simple"
+		case tokens[1] == "message-digest":
+			o.AuthMode = "md5"
+		case tokens[1] == "key-chain" && len(tokens) >= 3:
+			o.AuthMode = "key-chain"
+			o.KeyChain = tokens[2]
+		}
+	case "authentication-key":
+		o.AuthKey, o.AuthKeyEncrypted = parseCiscoKey(tokens[1:])
+		if o.AuthMode == "" {
+			o.AuthMode = "simple"
+		}
+	case "message-digest-key":
+		if len(tokens) >= 4 {
+			fmt.Sscanf(tokens[1], "%d", &o.AuthKeyID)
+			o.AuthKey, o.AuthKeyEncrypted = parseCiscoKey(tokens[3:])
+			// Ключ MD5 имеет смысл только с режимом message-digest, заданным
+			// на интерфейсе или для всей зоны.
+			if o.AuthMode == "" {
+				o.AuthMode = "md5"
+			}
+		}
+	default:
+		if len(tokens) >= 3 && isNumber(tokens[0]) && tokens[1] == "area" {
+			fmt.Sscanf(tokens[0], "%d", &o.ProcessID)
+			o.Area = tokens[2]
+		}
 	}
-	if currentVlan != nil {
-		cfg.Vlans = append(cfg.Vlans, *currentVlan)
+}
+
+// parseCiscoKey возвращает ключ "[0|7] <key>"; ключи типа 7 расшифровываются.
+func parseCiscoKey(tokens []string) (string, bool) {
+	switch {
+	case len(tokens) >= 2 && tokens[0] == "0":
+		return tokens[1], false
+	case len(tokens) >= 2 && tokens[0] == "7":
+		if plain, ok := decodeCiscoType7(tokens[1]); ok {
+			return plain, false
+		}
+		return tokens[1], true
+	case len(tokens) >= 1:
+		return tokens[len(tokens)-1], false
 	}
-	for _, inIf := range natInside {
-		for _, outIf := range natOutside {
-			cfg.NAT = append(cfg.NAT, model.NAT{
-				Inside:  inIf,
-				Outside: outIf,
-			})
+	return "", false
+}
+
+// decodeCiscoType7 расшифровывает обратимые пароли Cisco типа 7.
+func decodeCiscoType7(enc string) (string, bool) {
+	const xlat = "dsfd;kfoA,.iyewrkldJKDHSUBsgvca69834ncxv9873254k;fg87"
+	if len(enc) < 4 || len(enc)%2 != 0 {
+		return "", false
+	}
+	var seed int
+	if _, err := fmt.Sscanf(enc[:2], "%d", &seed); err != nil || seed < 0 || seed >= len(xlat) {
+		return "", false
+	}
+	var out []byte
+	for i := 2; i+1 < len(enc); i += 2 {
+		var b int
+		if _, err := fmt.Sscanf(enc[i:i+2], "%x", &b); err != nil {
+			return "", false
 		}
+		out = append(out, byte(b)^xlat[(seed+(i-2)/2)%len(xlat)])
 	}
+	return string(out), true
+}
 
-	return cfg, nil
+// parseCiscoOSPFLine разбирает команды режима "router ospf".
+func parseCiscoOSPFLine(p *model.OSPFProcess, line string) bool {
+	parts := strings.Fields(line)
+	switch {
+	case strings.HasPrefix(line, "router-id ") && len(parts) == 2:
+		p.RouterID = parts[1]
+	case line == "passive-interface default":
+		p.PassiveDefault = true
+	case strings.HasPrefix(line, "passive-interface "):
+		p.PassiveInterfaces = append(p.PassiveInterfaces, strings.TrimPrefix(line, "passive-interface "))
+	case strings.HasPrefix(line, "no passive-interface "):
+		p.NoPassiveInterfaces = append(p.NoPassiveInterfaces, strings.TrimPrefix(line, "no passive-interface "))
+	case strings.HasPrefix(line, "network ") && len(parts) >= 5 && parts[3] == "area":
+		p.Networks = append(p.Networks, model.OSPFNetwork{Network: parts[1], Wildcard: parts[2], Area: parts[4]})
+	case strings.HasPrefix(line, "area ") && len(parts) >= 3:
+		parseCiscoOSPFArea(p.AreaRef(parts[1]), parts[2:])
+	case strings.HasPrefix(line, "default-information originate"):
+		p.DefaultInformation = parseCiscoDefaultOriginate(parts[2:])
+	case strings.HasPrefix(line, "redistribute ") && len(parts) >= 2:
+		p.Redistribute = append(p.Redistribute, parseCiscoRedistribution(parts[1:]))
+	case strings.HasPrefix(line, "auto-cost reference-bandwidth ") && len(parts) >= 3:
+		fmt.Sscanf(parts[2], "%d", &p.ReferenceBandwidth)
+	case strings.HasPrefix(line, "timers throttle spf ") && len(parts) >= 6:
+		timers := &model.SPFTimers{}
+		fmt.Sscanf(strings.Join(parts[3:6], " "), "%d %d %d", &timers.Start, &timers.Hold, &timers.Max)
+		p.SPFTimers = timers
+	default:
+		return false
+	}
+	return true
+}
+
+// parseCiscoRIPLine разбирает команды режима "router rip".
+func parseCiscoRIPLine(p *model.RIPProcess, line string) bool {
+	parts := strings.Fields(line)
+	switch {
+	case strings.HasPrefix(line, "version ") && len(parts) == 2:
+		fmt.Sscanf(parts[1], "%d", &p.Version)
+	case strings.HasPrefix(line, "network ") && len(parts) >= 2:
+		p.Networks = append(p.Networks, parts[1])
+	case line == "no auto-summary":
+		p.NoAutoSummary = true
+	case line == "passive-interface default":
+		p.PassiveDefault = true
+	case strings.HasPrefix(line, "passive-interface "):
+		p.PassiveInterfaces = append(p.PassiveInterfaces, strings.TrimPrefix(line, "passive-interface "))
+	case strings.HasPrefix(line, "no passive-interface "):
+		p.NoPassiveInterfaces = append(p.NoPassiveInterfaces, strings.TrimPrefix(line, "no passive-interface "))
+	case strings.HasPrefix(line, "default-information originate"):
+		p.DefaultInformation = parseCiscoDefaultOriginate(parts[2:])
+	case strings.HasPrefix(line, "redistribute ") && len(parts) >= 2:
+		p.Redistribute = append(p.Redistribute, parseCiscoRedistribution(parts[1:]))
+	default:
+		return false
+	}
+	return true
+}
+
+// parseCiscoEIGRPLine разбирает команды режима "router eigrp <as>".
+func parseCiscoEIGRPLine(p *model.EIGRPProcess, line string) bool {
+	parts := strings.Fields(line)
+	switch {
+	case strings.HasPrefix(line, "eigrp router-id ") && len(parts) == 3:
+		p.RouterID = parts[2]
+	case strings.HasPrefix(line, "network ") && len(parts) >= 2:
+		n := model.EIGRPNetwork{Network: parts[1]}
+		if len(parts) >= 3 {
+			n.Wildcard = parts[2]
+		}
+		p.Networks = append(p.Networks, n)
+	case line == "no auto-summary":
+		p.NoAutoSummary = true
+	case line == "passive-interface default":
+		p.PassiveDefault = true
+	case strings.HasPrefix(line, "passive-interface "):
+		p.PassiveInterfaces = append(p.PassiveInterfaces, strings.TrimPrefix(line, "passive-interface "))
+	case strings.HasPrefix(line, "no passive-interface "):
+		p.NoPassiveInterfaces = append(p.NoPassiveInterfaces, strings.TrimPrefix(line, "no passive-interface "))
+	case strings.HasPrefix(line, "redistribute ") && len(parts) >= 2:
+		p.Redistribute = append(p.Redistribute, parseCiscoRedistribution(parts[1:]))
+	default:
+		return false
+	}
+	return true
+}
+
+// parseCiscoISISLine разбирает команды режима "router isis".
+func parseCiscoISISLine(p *model.ISISProcess, line string) bool {
+	parts := strings.Fields(line)
+	switch {
+	case strings.HasPrefix(line, "net ") && len(parts) == 2:
+		p.NET = append(p.NET, parts[1])
+	case strings.HasPrefix(line, "is-type ") && len(parts) == 2:
+		p.Level = fromCiscoISISLevel(parts[1])
+	case strings.HasPrefix(line, "metric-style ") && len(parts) >= 2:
+		p.MetricStyle = parts[1]
+	case strings.HasPrefix(line, "passive-interface ") && len(parts) == 2:
+		p.PassiveInterfaces = append(p.PassiveInterfaces, parts[1])
+	case strings.HasPrefix(line, "default-information originate"):
+		p.DefaultInformation = parseCiscoDefaultOriginate(parts[2:])
+	case strings.HasPrefix(line, "redistribute ") && len(parts) >= 2:
+		p.Redistribute = append(p.Redistribute, parseCiscoRedistribution(parts[1:]))
+	default:
+		return false
+	}
+	return true
+}
+
+// parseCiscoInterfaceISIS разбирает хвост команд "isis" интерфейса.
+func parseCiscoInterfaceISIS(o *model.InterfaceISIS, tokens []string) {
+	if len(tokens) < 2 {
+		return
+	}
+	switch tokens[0] {
+	case "circuit-type":
+		o.CircuitType = fromCiscoISISLevel(tokens[1])
+	case "metric":
+		fmt.Sscanf(tokens[1], "%d", &o.Metric)
+	case "network":
+		o.PointToPoint = tokens[1] == "point-to-point"
+	}
+}
+
+// fromCiscoISISLevel приводит "level-2-only" к "level-2".
+func fromCiscoISISLevel(level string) string {
+	return strings.TrimSuffix(level, "-only")
+}
+
+// applyCiscoOSPFAreaAuth переносит аутентификацию зоны на интерфейсы этой зоны:
+// в Huawei режим задаётся на интерфейсе, а Cisco берёт его из зоны, если на
+// интерфейсе он не указан.
+func applyCiscoOSPFAreaAuth(cfg *model.Config, areaAuth map[int]map[string]string) {
+	if len(areaAuth) == 0 {
+		return
+	}
+	for i := range cfg.Interfaces {
+		iface := &cfg.Interfaces[i]
+		pid, area, ok := ciscoInterfaceOSPFArea(cfg, iface)
+		if !ok {
+			continue
+		}
+		for authArea, mode := range areaAuth[pid] {
+			if !sameOSPFArea(area, authArea) {
+				continue
+			}
+			if iface.OSPF == nil {
+				iface.OSPF = &model.InterfaceOSPF{}
+			}
+			if iface.OSPF.AuthMode == "" {
+				iface.OSPF.AuthMode = mode
+			}
+		}
+	}
+}
+
+// ciscoInterfaceOSPFArea находит процесс и зону интерфейса: по "ip ospf <pid> area"
+// или по первой команде network, покрывающей адрес интерфейса.
+func ciscoInterfaceOSPFArea(cfg *model.Config, iface *model.Interface) (int, string, bool) {
+	if iface.OSPF != nil && iface.OSPF.ProcessID != 0 && iface.OSPF.Area != "" {
+		return iface.OSPF.ProcessID, iface.OSPF.Area, true
+	}
+	addr, mask := model.SplitIPMask(iface.IP)
+	if mask == "" {
+		return 0, "", false
+	}
+	for _, p := range cfg.OSPFProcesses {
+		for _, n := range p.Networks {
+			if model.WildcardContains(n.Network, n.Wildcard, addr, "0.0.0.0") {
+				return p.ProcessID, n.Area, true
+			}
+		}
+	}
+	return 0, "", false
+}
+
+// sameOSPFArea сравнивает номера зон, записанные числом ("1") или адресом ("0.0.0.1").
+func sameOSPFArea(a, b string) bool {
+	if a == b {
+		return true
+	}
+	areaNumber := func(s string) (uint32, bool) {
+		if v, ok := model.ParseIPv4(s); ok {
+			return v, true
+		}
+		v, err := strconv.ParseUint(s, 10, 32)
+		return uint32(v), err == nil
+	}
+	x, ok1 := areaNumber(a)
+	y, ok2 := areaNumber(b)
+	return ok1 && ok2 && x == y
+}
+
+func parseCiscoOSPFArea(area *model.OSPFArea, tokens []string) {
+	switch tokens[0] {
+	case "stub":
+		area.Type = "stub"
+		if hasToken(tokens, "no-summary") {
+			area.Type = "totally-stubby"
+		}
+	case "nssa":
+		area.Type = "nssa"
+		if hasToken(tokens, "no-summary") {
+			area.Type = "totally-nssa"
+		}
+		area.NSSADefaultOriginate = hasToken(tokens, "default-information-originate")
+	case "default-cost":
+		if len(tokens) >= 2 {
+			fmt.Sscanf(tokens[1], "%d", &area.DefaultCost)
+		}
+	case "range":
+		if len(tokens) >= 3 {
+			r := model.OSPFAreaRange{Network: tokens[1], Mask: tokens[2]}
+			r.NotAdvertise = hasToken(tokens, "not-advertise")
+			for i := 3; i+1 < len(tokens); i++ {
+				if tokens[i] == "cost" {
+					fmt.Sscanf(tokens[i+1], "%d", &r.Cost)
+				}
+			}
+			area.Ranges = append(area.Ranges, r)
+		}
+	}
+}
+
+// parseCiscoRedistribution разбирает "<proto> [process] [metric <n>] [metric-type <t>] [subnets] [tag <t>] [route-map <name>]".
+func parseCiscoRedistribution(tokens []string) model.Redistribution {
+	r := model.Redistribution{Protocol: tokens[0]}
+	idx := 1
+	if idx < len(tokens) && (isNumber(tokens[idx]) || (r.Protocol == "isis" && !isRedistributionKeyword(tokens[idx]))) {
+		r.Process = tokens[idx]
+		idx++
+	}
+	for ; idx+1 < len(tokens); idx++ {
+		switch tokens[idx] {
+		case "metric":
+			// EIGRP задаёт составную метрику из пяти чисел.
+			if idx+5 < len(tokens) && isNumber(tokens[idx+2]) && isNumber(tokens[idx+5]) {
+				r.EIGRPMetric = strings.Join(tokens[idx+1:idx+6], " ")
+				idx += 5
+				break
+			}
+			fmt.Sscanf(tokens[idx+1], "%d", &r.Metric)
+			idx++
+		case "metric-type":
+			fmt.Sscanf(tokens[idx+1], "%d", &r.MetricType)
+			idx++
+		case "tag":
+			fmt.Sscanf(tokens[idx+1], "%d", &r.Tag)
+			idx++
+		case "route-map":
+			r.RouteMap = tokens[idx+1]
+			idx++
+		}
+	}
+	return r
+}
+
+func isRedistributionKeyword(tok string) bool {
+	switch tok {
+	case "metric", "metric-type", "subnets", "tag", "route-map", "level-1", "level-2", "level-1-2":
+		return true
+	}
+	return false
+}
+
+func parseCiscoDefaultOriginate(tokens []string) *model.DefaultOriginate {
+	d := &model.DefaultOriginate{}
+	for idx := 0; idx < len(tokens); idx++ {
+		switch tokens[idx] {
+		case "always":
+			d.Always = true
+		case "metric":
+			if idx+1 < len(tokens) {
+				fmt.Sscanf(tokens[idx+1], "%d", &d.Metric)
+				idx++
+			}
+		case "metric-type":
+			if idx+1 < len(tokens) {
+				fmt.Sscanf(tokens[idx+1], "%d", &d.MetricType)
+				idx++
+			}
+		case "route-map":
+			if idx+1 < len(tokens) {
+				d.RouteMap = tokens[idx+1]
+				idx++
+			}
+		}
+	}
+	return d
+}
+
+func hasToken(tokens []string, tok string) bool {
+	for _, t := range tokens {
+		if t == tok {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCiscoDHCPLease(tokens []string) *model.DHCPLease {
+	lease := &model.DHCPLease{}
+	if len(tokens) > 0 && strings.EqualFold(tokens[0], "infinite") {
+		lease.Infinite = true
+		return lease
+	}
+	values := []*int{&lease.Days, &lease.Hours, &lease.Minutes}
+	for i, tok := range tokens {
+		if i >= len(values) {
+			break
+		}
+		fmt.Sscanf(tok, "%d", values[i])
+	}
+	return lease
+}
+
+func addDHCPRelayServer(cfg *model.Config, iface, server string) {
+	server = strings.TrimSpace(server)
+	for i := range cfg.DHCP.Relays {
+		if cfg.DHCP.Relays[i].Interface == iface {
+			cfg.DHCP.Relays[i].Servers = append(cfg.DHCP.Relays[i].Servers, server)
+			return
+		}
+	}
+	cfg.DHCP.Relays = append(cfg.DHCP.Relays, model.DHCPRelay{
+		Interface: iface,
+		Servers:   []string{server},
+	})
+}
+
+// parseCiscoStaticNAT разбирает хвост команды "ip nat inside source static".
+func parseCiscoStaticNAT(tokens []string) (model.StaticNAT, bool) {
+	nat := model.StaticNAT{}
+	if len(tokens) > 0 && (tokens[0] == "tcp" || tokens[0] == "udp") {
+		nat.Protocol = tokens[0]
+		tokens = tokens[1:]
+	}
+	if len(tokens) < 2 {
+		return model.StaticNAT{}, false
+	}
+	nat.InsideAddress = tokens[0]
+	idx := 1
+	if nat.Protocol != "" {
+		nat.InsidePort = tokens[idx]
+		idx++
+	}
+	if idx >= len(tokens) {
+		return model.StaticNAT{}, false
+	}
+	if tokens[idx] == "interface" {
+		if idx+1 >= len(tokens) {
+			return model.StaticNAT{}, false
+		}
+		nat.GlobalInterface = tokens[idx+1]
+		idx += 2
+	} else {
+		nat.GlobalAddress = tokens[idx]
+		idx++
+	}
+	if nat.Protocol != "" && idx < len(tokens) {
+		nat.GlobalPort = tokens[idx]
+	}
+	return nat, true
+}
+
+// parseCiscoNATPolicy разбирает "<acl> interface <if>|pool <name> [overload]".
+func parseCiscoNATPolicy(tokens []string) (model.NATPolicy, bool) {
+	if len(tokens) < 3 {
+		return model.NATPolicy{}, false
+	}
+	policy := model.NATPolicy{}
+	if isNumber(tokens[0]) {
+		fmt.Sscanf(tokens[0], "%d", &policy.ACLID)
+	} else {
+		policy.ACLName = tokens[0]
+	}
+	switch tokens[1] {
+	case "interface":
+		policy.Outside = tokens[2]
+	case "pool":
+		policy.Pool = tokens[2]
+	default:
+		return model.NATPolicy{}, false
+	}
+	for _, tok := range tokens[3:] {
+		if tok == "overload" {
+			policy.Overload = true
+		}
+	}
+	return policy, true
+}
+
+// parseCiscoNATPool разбирает "<name> <start> <end> netmask <mask>|prefix-length <len>".
+func parseCiscoNATPool(tokens []string) (model.NATPool, bool) {
+	if len(tokens) < 3 {
+		return model.NATPool{}, false
+	}
+	pool := model.NATPool{Name: tokens[0], Start: tokens[1], End: tokens[2]}
+	if len(tokens) >= 5 {
+		switch tokens[3] {
+		case "netmask", "prefix-length":
+			pool.Mask = model.NormalizeMask(tokens[4])
+		}
+	}
+	return pool, true
+}
+
+// parseCiscoPrefixListLine разбирает "<name> [seq <n>] permit|deny <prefix>/<len> [ge <n>] [le <n>]".
+func parseCiscoPrefixListLine(tokens []string) (string, model.PrefixListEntry, bool) {
+	if len(tokens) < 3 {
+		return "", model.PrefixListEntry{}, false
+	}
+	name := tokens[0]
+	entry := model.PrefixListEntry{}
+	idx := 1
+	if tokens[idx] == "seq" && len(tokens) >= 5 {
+		fmt.Sscanf(tokens[idx+1], "%d", &entry.Sequence)
+		idx += 2
+	}
+	action := strings.ToLower(tokens[idx])
+	if action != "permit" && action != "deny" {
+		return "", model.PrefixListEntry{}, false
+	}
+	entry.Action = action
+	idx++
+	if idx >= len(tokens) {
+		return "", model.PrefixListEntry{}, false
+	}
+	prefix := strings.SplitN(tokens[idx], "/", 2)
+	if len(prefix) != 2 {
+		return "", model.PrefixListEntry{}, false
+	}
+	entry.Prefix = prefix[0]
+	fmt.Sscanf(prefix[1], "%d", &entry.Length)
+	for idx++; idx+1 < len(tokens); idx += 2 {
+		switch tokens[idx] {
+		case "ge":
+			fmt.Sscanf(tokens[idx+1], "%d", &entry.GE)
+		case "le":
+			fmt.Sscanf(tokens[idx+1], "%d", &entry.LE)
+		}
+	}
+	return name, entry, true
+}
+
+func parseCiscoRouteMapClause(entry *model.RouteMapEntry, line string) {
+	parts := strings.Fields(line)
+	switch {
+	case strings.HasPrefix(line, "match ip address prefix-list ") && len(parts) > 4:
+		entry.MatchPrefixLists = append(entry.MatchPrefixLists, parts[4:]...)
+	case strings.HasPrefix(line, "match ip address ") && len(parts) > 3:
+		entry.MatchACLs = append(entry.MatchACLs, parts[3:]...)
+	case strings.HasPrefix(line, "match tag ") && len(parts) > 2:
+		entry.MatchTags = append(entry.MatchTags, parts[2:]...)
+	case strings.HasPrefix(line, "match interface ") && len(parts) > 2:
+		entry.MatchInterfaces = append(entry.MatchInterfaces, parts[2:]...)
+	case strings.HasPrefix(line, "set metric ") && len(parts) == 3:
+		entry.SetMetric = parts[2]
+	case strings.HasPrefix(line, "set tag ") && len(parts) == 3:
+		entry.SetTag = parts[2]
+	case strings.HasPrefix(line, "set local-preference ") && len(parts) == 3:
+		entry.SetLocalPreference = parts[2]
+	case strings.HasPrefix(line, "set ip next-hop ") && len(parts) == 4:
+		entry.SetNextHop = parts[3]
+	default:
+		entry.Raw = append(entry.Raw, line)
+	}
+}
+
+func addPrefixListEntry(cfg *model.Config, name string, entry model.PrefixListEntry) {
+	for i := range cfg.PrefixLists {
+		if cfg.PrefixLists[i].Name == name {
+			cfg.PrefixLists[i].Entries = append(cfg.PrefixLists[i].Entries, entry)
+			return
+		}
+	}
+	cfg.PrefixLists = append(cfg.PrefixLists, model.PrefixList{Name: name, Entries: []model.PrefixListEntry{entry}})
+}
+
+func addRouteMapEntry(cfg *model.Config, name string, entry model.RouteMapEntry) {
+	for i := range cfg.RouteMaps {
+		if cfg.RouteMaps[i].Name == name {
+			cfg.RouteMaps[i].Entries = append(cfg.RouteMaps[i].Entries, entry)
+			return
+		}
+	}
+	cfg.RouteMaps = append(cfg.RouteMaps, model.RouteMap{Name: name, Entries: []model.RouteMapEntry{entry}})
+}
+
+// parseCiscoRoute разбирает "<dst> <mask> [iface] [next-hop] [distance] [tag <t>] [permanent|track <n>] [name <name>]".
+func parseCiscoRoute(tokens []string) (model.Route, bool) {
+	if len(tokens) < 3 {
+		return model.Route{}, false
+	}
+	route := model.Route{Destination: tokens[0], Mask: tokens[1]}
+	idx := 2
+	if !model.IsIPv4(tokens[idx]) && !isNumber(tokens[idx]) {
+		route.Interface = tokens[idx]
+		idx++
+	}
+	if idx < len(tokens) && model.IsIPv4(tokens[idx]) {
+		route.Gateway = tokens[idx]
+		idx++
+	}
+	if route.Gateway == "" && route.Interface == "" {
+		return model.Route{}, false
+	}
+	if idx < len(tokens) && isNumber(tokens[idx]) {
+		fmt.Sscanf(tokens[idx], "%d", &route.Distance)
+		idx++
+	}
+	for ; idx < len(tokens); idx++ {
+		switch tokens[idx] {
+		case "permanent":
+			route.Permanent = true
+		case "tag":
+			if idx+1 < len(tokens) {
+				fmt.Sscanf(tokens[idx+1], "%d", &route.Tag)
+				idx++
+			}
+		case "track":
+			if idx+1 < len(tokens) {
+				route.Track = tokens[idx+1]
+				idx++
+			}
+		case "name":
+			if idx+1 < len(tokens) {
+				route.Name = tokens[idx+1]
+				idx++
+			}
+		}
+	}
+	return route, true
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
 }
 
 func getOrCreateACL(cfg *model.Config, id int, aclType string) *model.ACL {
@@ -199,6 +1057,215 @@ func getOrCreateACL(cfg *model.Config, id int, aclType string) *model.ACL {
 	return &cfg.ACLs[len(cfg.ACLs)-1]
 }
 
+// parseCiscoObjectGroupLine разбирает строку внутри "object-group network|service".
+// proto задаётся в заголовке группы сервисов в стиле ASA ("object-group service WEB tcp").
+func parseCiscoObjectGroupLine(g *model.ObjectGroup, proto string, tokens []string) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	switch tokens[0] {
+	case "description":
+		g.Description = strings.Join(tokens[1:], " ")
+		return true
+	case "group-object":
+		if len(tokens) >= 2 {
+			g.Groups = append(g.Groups, tokens[1])
+		}
+		return true
+	}
+	if g.Type == "network" {
+		switch {
+		case tokens[0] == "range" && len(tokens) >= 3:
+			g.Addresses = append(g.Addresses, model.GroupAddress{Address: tokens[1], End: tokens[2]})
+		case tokens[0] == "host" && len(tokens) >= 2:
+			g.Addresses = append(g.Addresses, model.GroupAddress{Address: tokens[1], Wildcard: "0.0.0.0"})
+		case len(tokens) >= 2 && model.IsIPv4(tokens[0]):
+			g.Addresses = append(g.Addresses, model.GroupAddress{Address: tokens[0], Wildcard: model.WildcardToMask(model.NormalizeMask(tokens[1]))})
+		case strings.Contains(tokens[0], "/"):
+			addr, mask := model.SplitIPMask(tokens[0])
+			g.Addresses = append(g.Addresses, model.GroupAddress{Address: addr, Wildcard: model.WildcardToMask(mask)})
+		default:
+			return false
+		}
+		return true
+	}
+
+	svc := model.GroupService{Protocol: proto}
+	idx := 0
+	if tokens[0] == "port-object" {
+		idx = 1
+	} else if _, ok := model.ProtocolNumber(tokens[0]); ok || tokens[0] == "ip" || tokens[0] == "tcp-udp" {
+		svc.Protocol = model.CanonicalProtocol(tokens[0])
+		idx = 1
+	}
+	if svc.Protocol == "" {
+		return false
+	}
+	if idx < len(tokens) && tokens[idx] == "source" {
+		if p, used := parsePortSpec("cisco", tokens[idx+1:], svc.Protocol); used > 0 {
+			svc.SrcPort = p
+			idx += 1 + used
+		}
+	}
+	if p, used := parsePortSpec("cisco", tokens[idx:], svc.Protocol); used > 0 {
+		svc.DstPort = p
+		idx += used
+	}
+	if svc.Protocol == "icmp" && idx < len(tokens) {
+		svc.ICMPType = tokens[idx]
+		idx++
+	}
+	// строка, разобранная не полностью, относится уже к следующему блоку
+	if idx != len(tokens) {
+		return false
+	}
+	g.Services = append(g.Services, svc)
+	return true
+}
+
+// parseCiscoTimeRangeLine разбирает "periodic <days> hh:mm to hh:mm" и
+// "absolute [start hh:mm dd Month yyyy] [end hh:mm dd Month yyyy]".
+// Периоды через несколько дней ("Monday 8:00 to Friday 18:00") сохраняются в Raw.
+func parseCiscoTimeRangeLine(t *model.TimeRange, line string) {
+	parts := strings.Fields(line)
+	if parts[0] == "absolute" {
+		abs := &model.AbsoluteTime{}
+		for idx := 1; idx+4 < len(parts); idx += 5 {
+			date, ok := parseCiscoDate(parts[idx+2 : idx+5])
+			if !ok {
+				t.Raw = append(t.Raw, line)
+				return
+			}
+			switch parts[idx] {
+			case "start":
+				abs.StartTime, abs.StartDate = normalizeClock(parts[idx+1]), date
+			case "end":
+				abs.EndTime, abs.EndDate = normalizeClock(parts[idx+1]), date
+			}
+		}
+		t.Absolute = abs
+		return
+	}
+	p := model.PeriodicTime{}
+	idx := 1
+	for ; idx < len(parts) && !strings.Contains(parts[idx], ":"); idx++ {
+		p.Days = append(p.Days, canonicalWeekday(parts[idx]))
+	}
+	if len(p.Days) == 0 || idx+2 != len(parts)-1 || parts[idx+1] != "to" {
+		t.Raw = append(t.Raw, line)
+		return
+	}
+	p.Start = normalizeClock(parts[idx])
+	p.End = normalizeClock(parts[idx+2])
+	t.Periodic = append(t.Periodic, p)
+}
+
+var ciscoMonths = []string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"}
+
+// parseCiscoDate переводит "01 January 2026" в "2026-01-01".
+func parseCiscoDate(tokens []string) (string, bool) {
+	var day, year int
+	if _, err := fmt.Sscanf(tokens[0]+" "+tokens[2], "%d %d", &day, &year); err != nil {
+		return "", false
+	}
+	for i, m := range ciscoMonths {
+		if strings.HasPrefix(m, strings.ToLower(tokens[1])) {
+			return fmt.Sprintf("%04d-%02d-%02d", year, i+1, day), true
+		}
+	}
+	return "", false
+}
+
+// canonicalWeekday приводит дни недели обоих производителей к виду модели.
+func canonicalWeekday(day string) string {
+	day = strings.ToLower(day)
+	switch day {
+	case "daily", "weekdays", "weekend":
+		return day
+	case "working-day":
+		return "weekdays"
+	case "off-day":
+		return "weekend"
+	}
+	if len(day) > 3 {
+		day = day[:3]
+	}
+	return day
+}
+
+// normalizeClock дополняет время до "hh:mm".
+func normalizeClock(clock string) string {
+	var h, m int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &h, &m); err != nil {
+		return clock
+	}
+	return fmt.Sprintf("%02d:%02d", h, m)
+}
+
+// parseLineRange разбирает "<type> <first> [<last>]" из "line" / "user-interface".
+func parseLineRange(tokens []string) (model.Line, bool) {
+	if len(tokens) < 2 || !isNumber(tokens[1]) {
+		return model.Line{}, false
+	}
+	l := model.Line{Type: strings.ToLower(tokens[0])}
+	if l.Type == "console" {
+		l.Type = "con"
+	}
+	fmt.Sscanf(tokens[1], "%d", &l.First)
+	if len(tokens) >= 3 && isNumber(tokens[2]) {
+		fmt.Sscanf(tokens[2], "%d", &l.Last)
+	}
+	return l, true
+}
+
+// getOrCreateNamedACL возвращает ACL из блока "ip access-list"; числовое имя
+// означает обычный нумерованный список.
+func getOrCreateNamedACL(cfg *model.Config, name, aclType string) *model.ACL {
+	if isNumber(name) {
+		var id int
+		fmt.Sscanf(name, "%d", &id)
+		return getOrCreateACL(cfg, id, aclType)
+	}
+	for i := range cfg.ACLs {
+		if cfg.ACLs[i].Name == name {
+			return &cfg.ACLs[i]
+		}
+	}
+	cfg.ACLs = append(cfg.ACLs, model.ACL{Name: name, Type: aclType})
+	return &cfg.ACLs[len(cfg.ACLs)-1]
+}
+
+// parseCiscoNamedACLEntry разбирает строку "[seq] permit|deny ..." внутри "ip access-list".
+func parseCiscoNamedACLEntry(acl *model.ACL, tokens []string) bool {
+	seq := 0
+	if len(tokens) > 0 && isNumber(tokens[0]) {
+		fmt.Sscanf(tokens[0], "%d", &seq)
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return false
+	}
+	switch strings.ToLower(tokens[0]) {
+	case "permit", "deny", "remark":
+	default:
+		return false
+	}
+	var rule model.ACLRule
+	var ok bool
+	if strings.ToLower(tokens[0]) == "remark" {
+		rule, ok = parseCiscoRemark(tokens)
+	} else if acl.Type == "extended" {
+		rule, ok = parseCiscoExtendedACLRule(tokens)
+	} else {
+		rule, ok = parseCiscoStandardACLRule(tokens)
+	}
+	if ok {
+		rule.Sequence = seq
+		acl.Rules = append(acl.Rules, rule)
+	}
+	return true
+}
+
 func parseCiscoACLLine(line string) (int, string, model.ACLRule, bool) {
 	parts := strings.Fields(line)
 	if len(parts) < 4 {
@@ -210,6 +1277,11 @@ func parseCiscoACLLine(line string) (int, string, model.ACLRule, bool) {
 		return 0, "", model.ACLRule{}, false
 	}
 
+	if strings.ToLower(parts[2]) == "remark" {
+		// тип списка определяется по номеру: текст примечания может содержать имена протоколов
+		rule, ok := parseCiscoRemark(parts[2:])
+		return aclID, inferCiscoACLType(aclID, parts[:3]), rule, ok
+	}
 	aclType := inferCiscoACLType(aclID, parts)
 	if aclType == "extended" {
 		rule, ok := parseCiscoExtendedACLRule(parts[2:])
@@ -219,14 +1291,22 @@ func parseCiscoACLLine(line string) (int, string, model.ACLRule, bool) {
 	return aclID, "standard", rule, ok
 }
 
+// parseCiscoRemark разбирает "remark <text>"; примечание сохраняется отдельной
+// записью перед правилом, к которому относится.
+func parseCiscoRemark(tokens []string) (model.ACLRule, bool) {
+	if len(tokens) < 2 {
+		return model.ACLRule{}, false
+	}
+	return model.ACLRule{Action: "remark", Remark: strings.Join(tokens[1:], " ")}, true
+}
+
 func inferCiscoACLType(id int, parts []string) string {
 	if (id >= 100 && id <= 199) || (id >= 2000 && id <= 2699) {
 		return "extended"
 	}
 	if len(parts) >= 5 {
 		proto := strings.ToLower(parts[3])
-		switch proto {
-		case "ip", "tcp", "udp", "icmp", "gre", "esp", "ahp", "ospf":
+		if _, ok := model.ProtocolNumber(proto); ok || proto == "ip" {
 			return "extended"
 		}
 	}
@@ -247,6 +1327,9 @@ func parseCiscoStandardACLRule(tokens []string) (model.ACLRule, bool) {
 	}
 	rule.Source = addr
 	rule.Wildcard = wildcard
+	if !parseCiscoACLQualifiers(&rule, tokens[1+used:]) {
+		rule = model.ACLRule{Action: rule.Action, Raw: strings.Join(tokens, " ")}
+	}
 	return rule, true
 }
 
@@ -257,56 +1340,115 @@ func parseCiscoExtendedACLRule(tokens []string) (model.ACLRule, bool) {
 	}
 	rule := model.ACLRule{
 		Action:   strings.ToLower(tokens[0]),
-		Protocol: strings.ToLower(tokens[1]),
+		Protocol: model.CanonicalProtocol(tokens[1]),
 	}
 	idx := 2
+	if rule.Protocol == "object-group" {
+		rule.Protocol = ""
+		rule.ServiceGroup = tokens[2]
+		idx = 3
+	}
 
-	src, srcWc, used := parseCiscoAddressSpec(tokens[idx:])
-	if used == 0 {
-		rule.Raw = strings.Join(tokens, " ")
-		return rule, true
+	if tokens[idx] == "object-group" && idx+1 < len(tokens) {
+		rule.SourceGroup = tokens[idx+1]
+		idx += 2
+	} else {
+		src, srcWc, used := parseCiscoAddressSpec(tokens[idx:])
+		if used == 0 {
+			rule.Raw = strings.Join(tokens, " ")
+			return rule, true
+		}
+		rule.Source = src
+		rule.Wildcard = srcWc
+		idx += used
 	}
-	rule.Source = src
-	rule.Wildcard = srcWc
-	idx += used
 
 	if rule.Protocol == "tcp" || rule.Protocol == "udp" {
-		if p, used := parsePortSpec(tokens[idx:]); used > 0 {
+		if p, used := parsePortSpec("cisco", tokens[idx:], rule.Protocol); used > 0 {
 			rule.SrcPort = p
 			idx += used
 		}
 	}
 
-	dst, dstWc, used := parseCiscoAddressSpec(tokens[idx:])
-	if used == 0 {
-		rule.Raw = strings.Join(tokens, " ")
-		return rule, true
+	if idx+1 < len(tokens) && tokens[idx] == "object-group" {
+		rule.DestinationGroup = tokens[idx+1]
+		idx += 2
+	} else {
+		dst, dstWc, used := parseCiscoAddressSpec(tokens[idx:])
+		if used == 0 {
+			rule.Raw = strings.Join(tokens, " ")
+			return rule, true
+		}
+		rule.Destination = dst
+		rule.DstWildcard = dstWc
+		idx += used
 	}
-	rule.Destination = dst
-	rule.DstWildcard = dstWc
-	idx += used
 
 	if rule.Protocol == "tcp" || rule.Protocol == "udp" {
-		if p, used := parsePortSpec(tokens[idx:]); used > 0 {
+		if p, used := parsePortSpec("cisco", tokens[idx:], rule.Protocol); used > 0 {
 			rule.DstPort = p
 			idx += used
 		}
 	}
 
-	if idx < len(tokens) {
-		// Keep extra qualifiers (log, established...) as raw tail for visibility.
-		tail := strings.Join(tokens[idx:], " ")
-		if tail != "" {
-			if rule.Raw == "" {
-				rule.Raw = tail
-			} else {
-				rule.Raw = rule.Raw + " " + tail
-			}
+	if rule.Protocol == "icmp" && idx < len(tokens) && !isCiscoACLQualifier(tokens[idx]) {
+		rule.ICMPType = tokens[idx]
+		idx++
+		if isNumber(rule.ICMPType) && idx < len(tokens) && isNumber(tokens[idx]) {
+			rule.ICMPCode = tokens[idx]
+			idx++
 		}
 	}
+	if !parseCiscoACLQualifiers(&rule, tokens[idx:]) {
+		// неизвестный хвост: правило сохраняется целиком
+		rule = model.ACLRule{Action: rule.Action, Protocol: rule.Protocol, Raw: strings.Join(tokens, " ")}
+	}
 	return rule, true
 }
 
+// parseCiscoACLQualifiers разбирает хвост правила после адресов и портов.
+// Возвращает false, если встретилось неизвестное слово.
+func parseCiscoACLQualifiers(rule *model.ACLRule, tokens []string) bool {
+	for idx := 0; idx < len(tokens); idx++ {
+		tok := strings.ToLower(tokens[idx])
+		switch tok {
+		case "log", "log-input":
+			rule.Log = true
+		case "established":
+			rule.Established = true
+		case "fragments":
+			rule.Fragments = true
+		case "dscp", "precedence", "tos", "time-range":
+			if idx+1 >= len(tokens) {
+				return false
+			}
+			value := tokens[idx+1]
+			idx++
+			switch tok {
+			case "dscp":
+				rule.DSCP = value
+			case "precedence":
+				rule.Precedence = value
+			case "tos":
+				rule.TOS = value
+			case "time-range":
+				rule.TimeRange = value
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isCiscoACLQualifier(tok string) bool {
+	switch strings.ToLower(tok) {
+	case "log", "log-input", "established", "fragments", "dscp", "precedence", "tos", "time-range":
+		return true
+	}
+	return false
+}
+
 func parseCiscoAddressSpec(tokens []string) (addr string, wildcard string, used int) {
 	if len(tokens) == 0 {
 		return "", "", 0
@@ -320,24 +1462,30 @@ func parseCiscoAddressSpec(tokens []string) (addr string, wildcard string, used
 		}
 		return "", "", 0
 	default:
-		if len(tokens) >= 2 {
+		if len(tokens) >= 2 && model.IsIPv4(tokens[1]) {
 			return tokens[0], tokens[1], 2
 		}
+		// адрес без маски в стандартном ACL означает хост
+		if model.IsIPv4(tokens[0]) {
+			return tokens[0], "0.0.0.0", 1
+		}
 		return "", "", 0
 	}
 }
 
-func parsePortSpec(tokens []string) (string, int) {
+// parsePortSpec разбирает "eq|neq|gt|lt <port>" и "range <from> <to>";
+// имена портов (ключевые слова vendor) заменяются номерами.
+func parsePortSpec(vendor string, tokens []string, proto string) (string, int) {
 	if len(tokens) < 2 {
 		return "", 0
 	}
 	op := strings.ToLower(tokens[0])
 	switch op {
 	case "eq", "neq", "gt", "lt":
-		return op + " " + tokens[1], 2
+		return model.CanonicalPortSpec(vendor, proto, op+" "+tokens[1]), 2
 	case "range":
 		if len(tokens) >= 3 {
-			return op + " " + tokens[1] + " " + tokens[2], 3
+			return model.CanonicalPortSpec(vendor, proto, op+" "+tokens[1]+" "+tokens[2]), 3
 		}
 	}
 	return "", 0
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"converter/model"
+)
+
+func parseCiscoText(t *testing.T, text string) *model.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cisco.cfg")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ParseCisco(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestParseCiscoOSPFAuthentication(t *testing.T) {
+	cfg := parseCiscoText(t, `interface GigabitEthernet0/1
+ ip address 10.0.0.1 255.255.255.0
+ ip ospf message-digest-key 2 md5 secret
+!
+interface GigabitEthernet0/2
+ ip address 10.1.0.1 255.255.255.0
+!
+interface GigabitEthernet0/3
+ ip address 10.2.0.1 255.255.255.0
+ ip ospf 1 area 1
+!
+interface GigabitEthernet0/4
+ ip address 10.3.0.1 255.255.255.0
+!
+router ospf 1
+ network 10.0.0.0 0.0.0.255 area 0
+ network 10.1.0.0 0.0.0.255 area 0
+ area 0.0.0.0 authentication message-digest
+ area 1 authentication
+!
+`)
+	want := map[string]string{
+		"GigabitEthernet0/1": "md5",
+		"GigabitEthernet0/2": "md5",
+		"GigabitEthernet0/3": "simple",
+		"GigabitEthernet0/4": "",
+	}
+	for _, iface := range cfg.Interfaces {
+		var mode string
+		if iface.OSPF != nil {
+			mode = iface.OSPF.AuthMode
+		}
+		if mode != want[iface.Name] {
+			t.Errorf("%s: got auth mode %q, want %q", iface.Name, mode, want[iface.Name])
+		}
+	}
+	if o := cfg.Interfaces[0].OSPF; o.AuthKeyID != 2 || o.AuthKey != "secret" {
+		t.Errorf("got key %d %q, want 2 \"secret\"", o.AuthKeyID, o.AuthKey)
+	}
+}
+
+func TestParseCiscoKeepsVRFRoutes(t *testing.T) {
+	cfg := parseCiscoText(t, `ip route vrf RED 10.0.0.0 255.0.0.0 192.0.2.1
+ip route 0.0.0.0 0.0.0.0 192.0.2.254
+`)
+	if len(cfg.Routes) != 1 || cfg.Routes[0].Destination != "0.0.0.0" {
+		t.Errorf("got routes %+v, want the default route only", cfg.Routes)
+	}
+	if len(cfg.VRFRoutes) != 1 || cfg.VRFRoutes[0] != "ip route vrf RED 10.0.0.0 255.0.0.0 192.0.2.1" {
+		t.Errorf("got VRF routes %q", cfg.VRFRoutes)
+	}
+}
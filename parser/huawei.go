@@ -22,16 +22,68 @@ func ParseHuawei(path string) (*model.Config, error) {
 	var currentVlan *model.Vlan
 	var currentOSPF int
 	var currentOSPFArea string
+	var currentRIP int
+	var inISIS bool
+	var currentISISTag string
 	var currentACLID int
+	var currentACLName string
+	var currentLine *model.Line
+	var currentGroup *model.ObjectGroup
+	var currentDHCPPool *model.DHCPPool
+	var currentIfacePool *model.DHCPPool
+	var currentNATPool *model.NATPool
+	var currentRouteMap string
+	var currentRouteMapEntry *model.RouteMapEntry
+
+	closeBlocks := func() {
+		if currentInterface != nil {
+			if currentIfacePool != nil {
+				currentIfacePool.Interface = currentInterface.Name
+				currentIfacePool.Name = currentInterface.Name
+				cfg.DHCP.Pools = append(cfg.DHCP.Pools, *currentIfacePool)
+				currentIfacePool = nil
+			}
+			cfg.Interfaces = append(cfg.Interfaces, *currentInterface)
+			currentInterface = nil
+		}
+		if currentVlan != nil {
+			cfg.Vlans = append(cfg.Vlans, *currentVlan)
+			currentVlan = nil
+		}
+		if currentDHCPPool != nil {
+			cfg.DHCP.Pools = append(cfg.DHCP.Pools, *currentDHCPPool)
+			currentDHCPPool = nil
+		}
+		currentOSPF = 0
+		currentOSPFArea = ""
+		currentRIP = 0
+		inISIS = false
+		currentACLID = 0
+		currentACLName = ""
+		currentNATPool = nil
+		if currentLine != nil {
+			cfg.Lines = append(cfg.Lines, *currentLine)
+			currentLine = nil
+		}
+		if currentGroup != nil {
+			cfg.ObjectGroups = append(cfg.ObjectGroups, *currentGroup)
+			currentGroup = nil
+		}
+		if currentRouteMapEntry != nil {
+			addRouteMapEntry(cfg, currentRouteMap, *currentRouteMapEntry)
+			currentRouteMapEntry = nil
+		}
+	}
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 
 		switch {
+		case line == "#":
+			closeBlocks()
+
 		case strings.HasPrefix(line, "vlan ") && !strings.HasPrefix(line, "vlan batch"):
-			if currentVlan != nil {
-				cfg.Vlans = append(cfg.Vlans, *currentVlan)
-			}
+			closeBlocks()
 			var id int
 			fmt.Sscanf(line, "vlan %d", &id)
 			currentVlan = &model.Vlan{ID: id}
@@ -44,14 +96,8 @@ func ParseHuawei(path string) (*model.Config, error) {
 			currentVlan = nil
 
 		case strings.HasPrefix(line, "interface "):
-			if currentInterface != nil {
-				cfg.Interfaces = append(cfg.Interfaces, *currentInterface)
-			}
-			name := strings.TrimPrefix(line, "interface ")
-			nameParts := strings.Fields(name)
-			if len(nameParts) == 2 && strings.EqualFold(nameParts[0], "vlanif") {
-				name = "Vlan" + nameParts[1]
-			}
+			closeBlocks()
+			name := normalizeHuaweiIfaceName(strings.TrimPrefix(line, "interface "))
 			currentInterface = &model.Interface{Name: name}
 
 		case strings.HasPrefix(line, "description ") && currentInterface != nil:
@@ -77,64 +123,202 @@ func ParseHuawei(path string) (*model.Config, error) {
 				currentInterface.IP = parts[2] + " " + parts[3]
 			}
 
+		case line == "dhcp select global" && currentInterface != nil:
+			cfg.DHCP.GlobalInterfaces = append(cfg.DHCP.GlobalInterfaces, currentInterface.Name)
+
+		case line == "dhcp select interface" && currentInterface != nil:
+			if currentIfacePool == nil {
+				currentIfacePool = &model.DHCPPool{}
+			}
+
+		case strings.HasPrefix(line, "dhcp server ") && currentInterface != nil:
+			if currentIfacePool == nil {
+				currentIfacePool = &model.DHCPPool{}
+			}
+			parseHuaweiDHCPPoolLine(cfg, currentIfacePool, strings.TrimPrefix(line, "dhcp server "))
+
+		case strings.HasPrefix(line, "dhcp relay server-ip ") && currentInterface != nil:
+			addDHCPRelayServer(cfg, currentInterface.Name, strings.TrimPrefix(line, "dhcp relay server-ip "))
+
 		case line == "quit" && currentInterface != nil:
-			cfg.Interfaces = append(cfg.Interfaces, *currentInterface)
-			currentInterface = nil
+			closeBlocks()
 
 		case strings.HasPrefix(line, "port link-type trunk") && currentInterface != nil:
 
 		case strings.HasPrefix(line, "port trunk allow-pass vlan ") && currentInterface != nil:
 			currentInterface.TrunkVlans = strings.TrimPrefix(line, "port trunk allow-pass vlan ")
 
-		case strings.HasPrefix(line, "ospf "):
-			fmt.Sscanf(line, "ospf %d", &currentOSPF)
+		case strings.HasPrefix(line, "bandwidth ") && currentInterface != nil:
+			// Huawei задаёт полосу в Мбит/с
+			var mbps int
+			fmt.Sscanf(line, "bandwidth %d", &mbps)
+			currentInterface.Bandwidth = mbps * 1000
 
-		case strings.HasPrefix(line, "router-id ") && currentOSPF != 0:
-			cfg.OSPFRouterID = strings.TrimPrefix(line, "router-id ")
+		case strings.HasPrefix(line, "traffic-filter ") && currentInterface != nil:
+			// traffic-filter inbound|outbound acl [name] <ref>
+			parts := strings.Fields(line)
+			if len(parts) >= 4 && parts[2] == "acl" {
+				ref := parts[len(parts)-1]
+				if parts[1] == "inbound" {
+					currentInterface.ACLIn = ref
+				} else if parts[1] == "outbound" {
+					currentInterface.ACLOut = ref
+				}
+			}
 
-		case line == "silent-interface all" && currentOSPF != 0:
-			cfg.OSPFPassiveDefault = true
+		case strings.HasPrefix(line, "ospf ") && currentInterface != nil:
+			if currentInterface.OSPF == nil {
+				currentInterface.OSPF = &model.InterfaceOSPF{}
+			}
+			parseHuaweiInterfaceOSPF(currentInterface.OSPF, strings.Fields(line)[1:])
 
-		case strings.HasPrefix(line, "undo silent-interface ") && currentOSPF != 0:
-			iface := strings.TrimPrefix(line, "undo silent-interface ")
-			cfg.OSPFNoPassiveIfaces = append(cfg.OSPFNoPassiveIfaces, normalizeOspfIfaceFromHuawei(iface))
+		case strings.HasPrefix(line, "ospf ") && currentInterface == nil:
+			closeBlocks()
+			parts := strings.Fields(line)
+			if len(parts) >= 2 && isNumber(parts[1]) {
+				fmt.Sscanf(parts[1], "%d", &currentOSPF)
+				p := cfg.OSPFProcessRef(currentOSPF)
+				for i := 2; i+1 < len(parts); i++ {
+					if parts[i] == "router-id" {
+						p.RouterID = parts[i+1]
+					}
+				}
+			}
+
+		case line == "quit" && currentOSPFArea != "":
+			currentOSPFArea = ""
 
 		case line == "quit" && currentOSPF != 0:
 			currentOSPF = 0
 
-		case strings.HasPrefix(line, "area "):
+		case currentOSPF != 0 && currentOSPFArea != "" && parseHuaweiOSPFAreaLine(cfg.OSPFProcessRef(currentOSPF), currentOSPFArea, line):
+
+		case strings.HasPrefix(line, "area ") && currentOSPF != 0:
 			parts := strings.Fields(line)
 			if len(parts) >= 2 {
 				currentOSPFArea = parts[1]
+				cfg.OSPFProcessRef(currentOSPF).AreaRef(currentOSPFArea)
 			}
 
-		case strings.HasPrefix(line, "network "):
+		case currentOSPF != 0 && parseHuaweiOSPFLine(cfg.OSPFProcessRef(currentOSPF), line):
+
+		case strings.HasPrefix(line, "isis ") && currentInterface != nil:
+			if currentInterface.ISIS == nil {
+				currentInterface.ISIS = &model.InterfaceISIS{}
+			}
+			parseHuaweiInterfaceISIS(cfg, currentInterface, strings.Fields(line)[1:])
+
+		case (line == "isis" || strings.HasPrefix(line, "isis ")) && currentInterface == nil:
+			closeBlocks()
+			inISIS = true
+			currentISISTag = "1"
 			parts := strings.Fields(line)
-			if len(parts) >= 3 && currentOSPF != 0 && currentOSPFArea != "" {
-				cfg.OSPF = append(cfg.OSPF, model.OSPF{
-					ProcessID: currentOSPF,
-					Network:   parts[1],
-					Wildcard:  parts[2],
-					Area:      currentOSPFArea,
-				})
+			if len(parts) >= 2 && isNumber(parts[1]) {
+				currentISISTag = parts[1]
 			}
+			cfg.ISISProcessRef(currentISISTag)
+
+		case line == "quit" && inISIS:
+			inISIS = false
+
+		case inISIS && parseHuaweiISISLine(cfg.ISISProcessRef(currentISISTag), line):
+
+		case (line == "rip" || strings.HasPrefix(line, "rip ")) && currentInterface == nil:
+			closeBlocks()
+			currentRIP = 1
+			parts := strings.Fields(line)
+			if len(parts) >= 2 && isNumber(parts[1]) {
+				fmt.Sscanf(parts[1], "%d", &currentRIP)
+			}
+			cfg.RIPProcessRef(currentRIP)
+
+		case line == "quit" && currentRIP != 0:
+			currentRIP = 0
+
+		case currentRIP != 0 && parseHuaweiRIPLine(cfg.RIPProcessRef(currentRIP), line):
+
+		case strings.HasPrefix(line, "ip pool "):
+			closeBlocks()
+			currentDHCPPool = &model.DHCPPool{Name: strings.TrimPrefix(line, "ip pool ")}
+
+		case line == "quit" && currentDHCPPool != nil:
+			closeBlocks()
+
+		case currentDHCPPool != nil && parseHuaweiDHCPPoolLine(cfg, currentDHCPPool, line):
+
+		case strings.HasPrefix(line, "ip ip-prefix "):
+			if name, entry, ok := parseHuaweiIPPrefixLine(strings.Fields(line)[2:]); ok {
+				addPrefixListEntry(cfg, name, entry)
+			}
+
+		case strings.HasPrefix(line, "route-policy "):
+			closeBlocks()
+			parts := strings.Fields(line)
+			if len(parts) >= 2 {
+				currentRouteMap = parts[1]
+				currentRouteMapEntry = &model.RouteMapEntry{Action: "permit"}
+				if len(parts) >= 3 {
+					currentRouteMapEntry.Action = strings.ToLower(parts[2])
+				}
+				if len(parts) >= 5 && parts[3] == "node" {
+					fmt.Sscanf(parts[4], "%d", &currentRouteMapEntry.Sequence)
+				}
+			}
+
+		case (strings.HasPrefix(line, "if-match ") || strings.HasPrefix(line, "apply ")) && currentRouteMapEntry != nil:
+			parseHuaweiRoutePolicyClause(currentRouteMapEntry, line)
+
+		case line == "quit" && currentRouteMapEntry != nil:
+			closeBlocks()
 
 		case strings.HasPrefix(line, "nat address-group "):
+			closeBlocks()
+			parts := strings.Fields(line)
+			var id int
+			if len(parts) >= 3 {
+				if _, err := fmt.Sscanf(parts[2], "%d", &id); err == nil {
+					pool := model.NATPool{Name: parts[2], ID: id}
+					if len(parts) >= 5 && model.IsIPv4(parts[3]) {
+						pool.Start = parts[3]
+						pool.End = parts[4]
+					}
+					cfg.NATPool = append(cfg.NATPool, pool)
+					currentNATPool = &cfg.NATPool[len(cfg.NATPool)-1]
+				}
+			}
+
+		case strings.HasPrefix(line, "section ") && currentNATPool != nil:
 			parts := strings.Fields(line)
-			if len(parts) >= 5 {
-				cfg.NAT = append(cfg.NAT, model.NAT{
-					Inside:  parts[len(parts)-2],
-					Outside: parts[len(parts)-1],
-				})
+			if len(parts) >= 4 && currentNATPool.Start == "" {
+				currentNATPool.Start = parts[2]
+				currentNATPool.End = parts[3]
 			}
 
 		case strings.HasPrefix(line, "nat outbound ") && currentInterface != nil:
-			var aclID int
-			if _, err := fmt.Sscanf(line, "nat outbound %d", &aclID); err == nil {
-				cfg.NATRule = append(cfg.NATRule, model.NATPolicy{
-					ACLID:   aclID,
-					Outside: currentInterface.Name,
-				})
+			if policy, ok := parseHuaweiNATOutbound(strings.Fields(line)[2:]); ok {
+				currentInterface.NAT = "outside"
+				policy.Outside = currentInterface.Name
+				cfg.NATRule = append(cfg.NATRule, policy)
+			}
+
+		case strings.HasPrefix(line, "nat server ") || strings.HasPrefix(line, "nat static "):
+			if nat, ok := parseHuaweiStaticNAT(strings.Fields(line)[2:]); ok {
+				if currentInterface != nil {
+					currentInterface.NAT = "outside"
+					nat.Interface = currentInterface.Name
+					if nat.GlobalInterface == "current-interface" {
+						nat.GlobalInterface = currentInterface.Name
+					}
+				}
+				cfg.StaticNAT = append(cfg.StaticNAT, nat)
+			}
+
+		case line == "nat static enable" && currentInterface != nil:
+			currentInterface.NAT = "outside"
+			for i := range cfg.StaticNAT {
+				if cfg.StaticNAT[i].Interface == "" {
+					cfg.StaticNAT[i].Interface = currentInterface.Name
+				}
 			}
 
 		case line == "smtp server enable":
@@ -146,17 +330,16 @@ func ParseHuawei(path string) (*model.Config, error) {
 		case strings.HasPrefix(line, "stp mode "):
 			cfg.STP.Mode = strings.TrimPrefix(line, "stp mode ")
 
+		case strings.HasPrefix(line, "ip route-static vpn-instance "):
+			cfg.VRFRoutes = append(cfg.VRFRoutes, line)
+
 		case strings.HasPrefix(line, "ip route-static "):
-			parts := strings.Fields(line)
-			if len(parts) >= 5 {
-				cfg.Routes = append(cfg.Routes, model.Route{
-					Destination: parts[2],
-					Mask:        parts[3],
-					Gateway:     parts[4],
-				})
+			if route, ok := parseHuaweiRoute(strings.Fields(line)[2:]); ok {
+				cfg.Routes = append(cfg.Routes, route)
 			}
 
 		case strings.HasPrefix(line, "acl number "):
+			closeBlocks()
 			parts := strings.Fields(line)
 			if len(parts) >= 3 {
 				var aclID int
@@ -166,14 +349,93 @@ func ParseHuawei(path string) (*model.Config, error) {
 				}
 			}
 
-		case strings.HasPrefix(line, "rule ") && currentACLID != 0:
+		case strings.HasPrefix(line, "user-interface "):
+			closeBlocks()
+			if l, ok := parseLineRange(strings.Fields(line)[1:]); ok {
+				currentLine = &l
+			}
+
+		case strings.HasPrefix(line, "acl ") && currentLine != nil:
+			// acl <ref> inbound|outbound
+			parts := strings.Fields(line)
+			if len(parts) >= 3 && parts[len(parts)-1] == "inbound" {
+				currentLine.ACLIn = parts[len(parts)-2]
+			} else if len(parts) >= 3 && parts[len(parts)-1] == "outbound" {
+				currentLine.ACLOut = parts[len(parts)-2]
+			}
+
+		case line == "quit" && currentLine != nil:
+			closeBlocks()
+
+		case strings.HasPrefix(line, "ip address-set ") || strings.HasPrefix(line, "ip service-set "):
+			closeBlocks()
+			parts := strings.Fields(line)
+			if len(parts) >= 3 {
+				groupType := "network"
+				if parts[1] == "service-set" {
+					groupType = "service"
+				}
+				currentGroup = &model.ObjectGroup{Name: parts[2], Type: groupType}
+			}
+
+		case line == "quit" && currentGroup != nil:
+			closeBlocks()
+
+		case currentGroup != nil && parseHuaweiObjectGroupLine(currentGroup, strings.Fields(line)):
+
+		case strings.HasPrefix(line, "time-range "):
+			closeBlocks()
+			parts := strings.Fields(line)
+			if len(parts) >= 3 {
+				parseHuaweiTimeRange(cfg.TimeRangeRef(parts[1]), parts[2:], line)
+			}
+
+		case strings.HasPrefix(line, "acl name "):
+			closeBlocks()
+			parts := strings.Fields(line)
+			if len(parts) >= 3 {
+				currentACLName = parts[2]
+				aclType := "advanced"
+				if len(parts) >= 4 && isNumber(parts[3]) {
+					fmt.Sscanf(parts[3], "%d", &currentACLID)
+					aclType = inferHuaweiACLType(currentACLID)
+				} else if len(parts) >= 4 {
+					aclType = parts[3]
+					// VRP пишет тип как "advance", в модели он называется "advanced".
+					if aclType == "advance" {
+						aclType = "advanced"
+					}
+				}
+				getOrCreateHuaweiNamedACL(cfg, currentACLName, currentACLID, aclType)
+			}
+
+		case strings.HasPrefix(line, "rule ") && (currentACLID != 0 || currentACLName != ""):
 			if rule, ok := parseHuaweiACLRule(line); ok {
-				acl := getOrCreateACL(cfg, currentACLID, inferHuaweiACLType(currentACLID))
-				acl.Rules = append(acl.Rules, rule)
+				var acl *model.ACL
+				if currentACLName != "" {
+					acl = getOrCreateHuaweiNamedACL(cfg, currentACLName, currentACLID, "")
+				} else {
+					acl = getOrCreateACL(cfg, currentACLID, inferHuaweiACLType(currentACLID))
+				}
+				if rule.Action == "remark" {
+					insertHuaweiRemark(acl, rule)
+				} else {
+					acl.Rules = append(acl.Rules, rule)
+				}
 			}
 
-		case line == "quit" && currentACLID != 0:
+		case strings.HasPrefix(line, "description ") && (currentACLID != 0 || currentACLName != ""):
+			var acl *model.ACL
+			if currentACLName != "" {
+				acl = getOrCreateHuaweiNamedACL(cfg, currentACLName, currentACLID, "")
+			} else {
+				acl = getOrCreateACL(cfg, currentACLID, inferHuaweiACLType(currentACLID))
+			}
+			acl.Description = strings.TrimPrefix(line, "description ")
+
+		case line == "quit" && (currentACLID != 0 || currentACLName != ""):
 			currentACLID = 0
+			currentACLName = ""
 		}
 	}
 
@@ -181,14 +443,603 @@ func ParseHuawei(path string) (*model.Config, error) {
 		return nil, err
 	}
 
-	if currentInterface != nil {
-		cfg.Interfaces = append(cfg.Interfaces, *currentInterface)
+	closeBlocks()
+	resolveHuaweiInterfacePools(cfg)
+	inferHuaweiNATInside(cfg)
+
+	return cfg, nil
+}
+
+// parseHuaweiDHCPPoolLine разбирает команды "ip pool" и "dhcp server" интерфейсного пула.
+func parseHuaweiDHCPPoolLine(cfg *model.Config, pool *model.DHCPPool, line string) bool {
+	parts := strings.Fields(line)
+	if len(parts) < 2 {
+		return false
 	}
-	if currentVlan != nil {
-		cfg.Vlans = append(cfg.Vlans, *currentVlan)
+	switch parts[0] {
+	case "gateway-list":
+		pool.DefaultRouters = parts[1:]
+	case "network":
+		pool.Network = parts[1]
+		if len(parts) >= 4 && parts[2] == "mask" {
+			pool.Mask = model.NormalizeMask(parts[3])
+		}
+	case "dns-list":
+		pool.DNSServers = parts[1:]
+	case "domain-name":
+		pool.DomainName = strings.Join(parts[1:], " ")
+	case "lease":
+		pool.Lease = parseHuaweiDHCPLease(parts[1:])
+	case "excluded-ip-address":
+		excluded := model.DHCPExcluded{Start: parts[1]}
+		if len(parts) >= 3 {
+			excluded.End = parts[2]
+		}
+		cfg.DHCP.Excluded = append(cfg.DHCP.Excluded, excluded)
+	default:
+		return false
 	}
+	return true
+}
 
-	return cfg, nil
+func parseHuaweiDHCPLease(tokens []string) *model.DHCPLease {
+	lease := &model.DHCPLease{}
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "unlimited":
+			lease.Infinite = true
+		case "day", "hour", "minute":
+			if i+1 < len(tokens) {
+				var v int
+				fmt.Sscanf(tokens[i+1], "%d", &v)
+				switch tokens[i] {
+				case "day":
+					lease.Days = v
+				case "hour":
+					lease.Hours = v
+				default:
+					lease.Minutes = v
+				}
+				i++
+			}
+		}
+	}
+	return lease
+}
+
+// resolveHuaweiInterfacePools вычисляет сеть и шлюз интерфейсных пулов по адресу интерфейса.
+func resolveHuaweiInterfacePools(cfg *model.Config) {
+	for i := range cfg.DHCP.Pools {
+		pool := &cfg.DHCP.Pools[i]
+		if pool.Interface == "" {
+			continue
+		}
+		for _, iface := range cfg.Interfaces {
+			if iface.Name != pool.Interface || iface.IP == "" {
+				continue
+			}
+			addr, mask := model.SplitIPMask(iface.IP)
+			pool.Network = model.SubnetOf(addr, mask)
+			pool.Mask = mask
+			if len(pool.DefaultRouters) == 0 {
+				pool.DefaultRouters = []string{addr}
+			}
+		}
+	}
+}
+
+// inferHuaweiNATInside отмечает внутренние интерфейсы NAT: в Huawei нет явной роли inside,
+// поэтому внутренними считаются L3-интерфейсы, чьи подсети попадают под ACL трансляции
+// или содержат внутренние адреса статического NAT.
+func inferHuaweiNATInside(cfg *model.Config) {
+	for i := range cfg.Interfaces {
+		iface := &cfg.Interfaces[i]
+		if iface.NAT != "" || iface.IP == "" {
+			continue
+		}
+		addr, mask := model.SplitIPMask(iface.IP)
+		if natPolicyCoversSubnet(cfg, addr, mask) {
+			iface.NAT = "inside"
+			continue
+		}
+		for _, n := range cfg.StaticNAT {
+			if model.SubnetContains(addr, mask, n.InsideAddress) {
+				iface.NAT = "inside"
+				break
+			}
+		}
+	}
+}
+
+func natPolicyCoversSubnet(cfg *model.Config, addr, mask string) bool {
+	for _, policy := range cfg.NATRule {
+		acl, ok := cfg.FindACL(policy.ACLRef())
+		if !ok {
+			continue
+		}
+		for _, rule := range acl.Rules {
+			if rule.Action != "permit" {
+				continue
+			}
+			if rule.Source == "" || rule.Source == "any" {
+				return true
+			}
+			if model.WildcardOverlaps(rule.Source, rule.Wildcard, addr, model.WildcardToMask(mask)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseHuaweiIPPrefixLine разбирает "<name> [index <n>] permit|deny <addr> <len> [greater-equal <n>] [less-equal <n>]".
+func parseHuaweiIPPrefixLine(tokens []string) (string, model.PrefixListEntry, bool) {
+	if len(tokens) < 4 {
+		return "", model.PrefixListEntry{}, false
+	}
+	name := tokens[0]
+	entry := model.PrefixListEntry{}
+	idx := 1
+	if tokens[idx] == "index" && len(tokens) >= 6 {
+		fmt.Sscanf(tokens[idx+1], "%d", &entry.Sequence)
+		idx += 2
+	}
+	action := strings.ToLower(tokens[idx])
+	if action != "permit" && action != "deny" {
+		return "", model.PrefixListEntry{}, false
+	}
+	entry.Action = action
+	idx++
+	if idx+1 >= len(tokens) {
+		return "", model.PrefixListEntry{}, false
+	}
+	entry.Prefix = tokens[idx]
+	fmt.Sscanf(tokens[idx+1], "%d", &entry.Length)
+	for idx += 2; idx+1 < len(tokens); idx += 2 {
+		switch tokens[idx] {
+		case "greater-equal":
+			fmt.Sscanf(tokens[idx+1], "%d", &entry.GE)
+		case "less-equal":
+			fmt.Sscanf(tokens[idx+1], "%d", &entry.LE)
+		}
+	}
+	return name, entry, true
+}
+
+func parseHuaweiRoutePolicyClause(entry *model.RouteMapEntry, line string) {
+	parts := strings.Fields(line)
+	switch {
+	case strings.HasPrefix(line, "if-match ip-prefix ") && len(parts) == 3:
+		entry.MatchPrefixLists = append(entry.MatchPrefixLists, parts[2])
+	case strings.HasPrefix(line, "if-match acl name ") && len(parts) == 4:
+		entry.MatchACLs = append(entry.MatchACLs, parts[3])
+	case strings.HasPrefix(line, "if-match acl ") && len(parts) == 3:
+		entry.MatchACLs = append(entry.MatchACLs, parts[2])
+	case strings.HasPrefix(line, "if-match tag ") && len(parts) == 3:
+		entry.MatchTags = append(entry.MatchTags, parts[2])
+	case strings.HasPrefix(line, "if-match interface ") && len(parts) >= 3:
+		entry.MatchInterfaces = append(entry.MatchInterfaces, normalizeHuaweiIfaceName(strings.Join(parts[2:], "")))
+	case strings.HasPrefix(line, "apply cost ") && len(parts) == 3:
+		entry.SetMetric = parts[2]
+	case strings.HasPrefix(line, "apply tag ") && len(parts) == 3:
+		entry.SetTag = parts[2]
+	case strings.HasPrefix(line, "apply local-preference ") && len(parts) == 3:
+		entry.SetLocalPreference = parts[2]
+	case strings.HasPrefix(line, "apply ip-address next-hop ") && len(parts) == 4:
+		entry.SetNextHop = parts[3]
+	default:
+		entry.Raw = append(entry.Raw, line)
+	}
+}
+
+// parseHuaweiRoute разбирает "<dst> <mask|len> [iface] [next-hop] [preference <p>] [tag <t>]
+// [permanent] [track bfd-session <name>|track nqa <admin> <test>] [description <text>]".
+func parseHuaweiRoute(tokens []string) (model.Route, bool) {
+	if len(tokens) < 3 {
+		return model.Route{}, false
+	}
+	route := model.Route{Destination: tokens[0], Mask: model.NormalizeMask(tokens[1])}
+	idx := 2
+	if !model.IsIPv4(tokens[idx]) {
+		route.Interface = normalizeHuaweiIfaceName(tokens[idx])
+		idx++
+	}
+	if idx < len(tokens) && model.IsIPv4(tokens[idx]) {
+		route.Gateway = tokens[idx]
+		idx++
+	}
+	for ; idx < len(tokens); idx++ {
+		switch tokens[idx] {
+		case "preference":
+			if idx+1 < len(tokens) {
+				fmt.Sscanf(tokens[idx+1], "%d", &route.Distance)
+				idx++
+			}
+		case "tag":
+			if idx+1 < len(tokens) {
+				fmt.Sscanf(tokens[idx+1], "%d", &route.Tag)
+				idx++
+			}
+		case "permanent":
+			route.Permanent = true
+		case "track":
+			end := idx + 3
+			if idx+1 < len(tokens) && tokens[idx+1] == "nqa" {
+				end = idx + 4
+			}
+			if end > len(tokens) {
+				end = len(tokens)
+			}
+			route.Track = strings.Join(tokens[idx+1:end], " ")
+			idx = end - 1
+		case "description":
+			route.Name = strings.Join(tokens[idx+1:], " ")
+			idx = len(tokens)
+		}
+	}
+	return route, true
+}
+
+// parseHuaweiInterfaceOSPF разбирает хвост команд "ospf" интерфейса.
+func parseHuaweiInterfaceOSPF(o *model.InterfaceOSPF, tokens []string) {
+	if len(tokens) < 2 {
+		return
+	}
+	switch tokens[0] {
+	case "cost":
+		fmt.Sscanf(tokens[1], "%d", &o.Cost)
+	case "network-type":
+		o.NetworkType = fromHuaweiOSPFNetworkType(tokens[1])
+	case "dr-priority":
+		var prio int
+		fmt.Sscanf(tokens[1], "%d", &prio)
+		o.Priority = &prio
+	case "timer":
+		if len(tokens) >= 3 {
+			switch tokens[1] {
+			case "hello":
+				fmt.Sscanf(tokens[2], "%d", &o.HelloInterval)
+			case "dead":
+				fmt.Sscanf(tokens[2], "%d", &o.DeadInterval)
+			}
+		}
+	case "enable":
+		if len(tokens) >= 4 && tokens[2] == "area" {
+			fmt.Sscanf(tokens[1], "%d", &o.ProcessID)
+			o.Area = tokens[3]
+		} else if len(tokens) >= 3 && tokens[1] == "area" {
+			o.Area = tokens[2]
+		}
+	case "authentication-mode":
+		parseHuaweiOSPFAuthentication(o, tokens[1:])
+	}
+}
+
+// parseHuaweiOSPFAuthentication разбирает "simple|md5|hmac-md5 [<key-id>] [plain|cipher] <key>" и "keychain <name>".
+func parseHuaweiOSPFAuthentication(o *model.InterfaceOSPF, tokens []string) {
+	switch tokens[0] {
+	case "keychain":
+		if len(tokens) >= 2 {
+			o.AuthMode = "key-chain"
+			o.KeyChain = tokens[1]
+		}
+		return
+	case "simple":
+		o.AuthMode = "simple"
+	case "md5", "hmac-md5":
+		o.AuthMode = "md5"
+	default:
+		return
+	}
+	rest := tokens[1:]
+	if o.AuthMode == "md5" && len(rest) > 0 && isNumber(rest[0]) {
+		fmt.Sscanf(rest[0], "%d", &o.AuthKeyID)
+		rest = rest[1:]
+	}
+	if len(rest) >= 2 {
+		o.AuthKeyEncrypted = rest[0] == "cipher" && strings.HasPrefix(rest[1], "%")
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		o.AuthKey = rest[0]
+	}
+}
+
+func fromHuaweiOSPFNetworkType(t string) string {
+	switch t {
+	case "p2p":
+		return "point-to-point"
+	case "p2mp":
+		return "point-to-multipoint"
+	case "nbma":
+		return "non-broadcast"
+	}
+	return t
+}
+
+// parseHuaweiOSPFLine разбирает команды режима "ospf".
+func parseHuaweiOSPFLine(p *model.OSPFProcess, line string) bool {
+	parts := strings.Fields(line)
+	switch {
+	case strings.HasPrefix(line, "router-id ") && len(parts) == 2:
+		p.RouterID = parts[1]
+	case line == "silent-interface all":
+		p.PassiveDefault = true
+	case strings.HasPrefix(line, "silent-interface "):
+		p.PassiveInterfaces = append(p.PassiveInterfaces, normalizeHuaweiIfaceName(strings.TrimPrefix(line, "silent-interface ")))
+	case strings.HasPrefix(line, "undo silent-interface "):
+		p.NoPassiveInterfaces = append(p.NoPassiveInterfaces, normalizeHuaweiIfaceName(strings.TrimPrefix(line, "undo silent-interface ")))
+	case strings.HasPrefix(line, "default-route-advertise"):
+		p.DefaultInformation = parseHuaweiDefaultRouteAdvertise(parts[1:])
+	case strings.HasPrefix(line, "import-route ") && len(parts) >= 2:
+		p.Redistribute = append(p.Redistribute, parseHuaweiImportRoute(parts[1:]))
+	case strings.HasPrefix(line, "bandwidth-reference ") && len(parts) == 2:
+		fmt.Sscanf(parts[1], "%d", &p.ReferenceBandwidth)
+	case strings.HasPrefix(line, "spf-schedule-interval intelligent-timer ") && len(parts) >= 5:
+		// порядок Huawei: max-interval start-interval hold-interval
+		timers := &model.SPFTimers{}
+		fmt.Sscanf(strings.Join(parts[2:5], " "), "%d %d %d", &timers.Max, &timers.Start, &timers.Hold)
+		p.SPFTimers = timers
+	default:
+		return false
+	}
+	return true
+}
+
+// parseHuaweiRIPLine разбирает команды режима "rip".
+func parseHuaweiRIPLine(p *model.RIPProcess, line string) bool {
+	parts := strings.Fields(line)
+	switch {
+	case strings.HasPrefix(line, "version ") && len(parts) == 2:
+		fmt.Sscanf(parts[1], "%d", &p.Version)
+	case strings.HasPrefix(line, "network ") && len(parts) >= 2:
+		p.Networks = append(p.Networks, parts[1])
+	case line == "undo summary":
+		p.NoAutoSummary = true
+	case line == "silent-interface all":
+		p.PassiveDefault = true
+	case strings.HasPrefix(line, "silent-interface "):
+		p.PassiveInterfaces = append(p.PassiveInterfaces, normalizeHuaweiIfaceName(strings.TrimPrefix(line, "silent-interface ")))
+	case strings.HasPrefix(line, "undo silent-interface "):
+		p.NoPassiveInterfaces = append(p.NoPassiveInterfaces, normalizeHuaweiIfaceName(strings.TrimPrefix(line, "undo silent-interface ")))
+	case strings.HasPrefix(line, "default-route originate"):
+		p.DefaultInformation = parseHuaweiDefaultRouteAdvertise(parts[2:])
+	case strings.HasPrefix(line, "import-route ") && len(parts) >= 2:
+		p.Redistribute = append(p.Redistribute, parseHuaweiImportRoute(parts[1:]))
+	default:
+		return false
+	}
+	return true
+}
+
+// parseHuaweiISISLine разбирает команды режима "isis".
+func parseHuaweiISISLine(p *model.ISISProcess, line string) bool {
+	parts := strings.Fields(line)
+	switch {
+	case strings.HasPrefix(line, "network-entity ") && len(parts) == 2:
+		p.NET = append(p.NET, parts[1])
+	case strings.HasPrefix(line, "is-level ") && len(parts) == 2:
+		p.Level = parts[1]
+	case strings.HasPrefix(line, "cost-style ") && len(parts) >= 2:
+		p.MetricStyle = parts[1]
+	case strings.HasPrefix(line, "default-route-advertise"):
+		p.DefaultInformation = parseHuaweiDefaultRouteAdvertise(parts[1:])
+	case strings.HasPrefix(line, "import-route ") && len(parts) >= 2:
+		p.Redistribute = append(p.Redistribute, parseHuaweiImportRoute(parts[1:]))
+	default:
+		return false
+	}
+	return true
+}
+
+// parseHuaweiInterfaceISIS разбирает хвост команд "isis" интерфейса.
+// "isis silent" переносится в список пассивных интерфейсов процесса.
+func parseHuaweiInterfaceISIS(cfg *model.Config, iface *model.Interface, tokens []string) {
+	o := iface.ISIS
+	switch {
+	case tokens[0] == "enable":
+		o.Tag = "1"
+		if len(tokens) >= 2 {
+			o.Tag = tokens[1]
+		}
+	case tokens[0] == "silent":
+		tag := o.Tag
+		if tag == "" {
+			tag = "1"
+		}
+		p := cfg.ISISProcessRef(tag)
+		p.PassiveInterfaces = append(p.PassiveInterfaces, iface.Name)
+	case tokens[0] == "circuit-level" && len(tokens) >= 2:
+		o.CircuitType = tokens[1]
+	case tokens[0] == "cost" && len(tokens) >= 2:
+		fmt.Sscanf(tokens[1], "%d", &o.Metric)
+	case tokens[0] == "circuit-type" && len(tokens) >= 2:
+		o.PointToPoint = tokens[1] == "p2p"
+	}
+}
+
+// parseHuaweiOSPFAreaLine разбирает команды режима "area" внутри OSPF.
+func parseHuaweiOSPFAreaLine(p *model.OSPFProcess, areaID, line string) bool {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return false
+	}
+	area := p.AreaRef(areaID)
+	switch {
+	case strings.HasPrefix(line, "network ") && len(parts) >= 3:
+		p.Networks = append(p.Networks, model.OSPFNetwork{Network: parts[1], Wildcard: parts[2], Area: areaID})
+	case parts[0] == "stub":
+		area.Type = "stub"
+		if hasToken(parts, "no-summary") {
+			area.Type = "totally-stubby"
+		}
+	case parts[0] == "nssa":
+		area.Type = "nssa"
+		if hasToken(parts, "no-summary") {
+			area.Type = "totally-nssa"
+		}
+		area.NSSADefaultOriginate = hasToken(parts, "default-route-advertise")
+	case strings.HasPrefix(line, "default-cost ") && len(parts) == 2:
+		fmt.Sscanf(parts[1], "%d", &area.DefaultCost)
+	case strings.HasPrefix(line, "abr-summary ") && len(parts) >= 3:
+		r := model.OSPFAreaRange{Network: parts[1], Mask: model.NormalizeMask(parts[2])}
+		r.NotAdvertise = hasToken(parts, "not-advertise")
+		for i := 3; i+1 < len(parts); i++ {
+			if parts[i] == "cost" {
+				fmt.Sscanf(parts[i+1], "%d", &r.Cost)
+			}
+		}
+		area.Ranges = append(area.Ranges, r)
+	default:
+		return false
+	}
+	return true
+}
+
+// parseHuaweiImportRoute разбирает "<proto> [process] [cost <n>] [type <t>] [tag <t>] [route-policy <name>]".
+func parseHuaweiImportRoute(tokens []string) model.Redistribution {
+	r := model.Redistribution{Protocol: fromHuaweiRouteProtocol(tokens[0])}
+	idx := 1
+	if idx < len(tokens) && isNumber(tokens[idx]) {
+		r.Process = tokens[idx]
+		idx++
+	}
+	for ; idx+1 < len(tokens); idx++ {
+		switch tokens[idx] {
+		case "cost":
+			fmt.Sscanf(tokens[idx+1], "%d", &r.Metric)
+			idx++
+		case "type":
+			fmt.Sscanf(tokens[idx+1], "%d", &r.MetricType)
+			idx++
+		case "tag":
+			fmt.Sscanf(tokens[idx+1], "%d", &r.Tag)
+			idx++
+		case "route-policy":
+			r.RouteMap = tokens[idx+1]
+			idx++
+		}
+	}
+	return r
+}
+
+func parseHuaweiDefaultRouteAdvertise(tokens []string) *model.DefaultOriginate {
+	d := &model.DefaultOriginate{}
+	for idx := 0; idx < len(tokens); idx++ {
+		switch tokens[idx] {
+		case "always":
+			d.Always = true
+		case "cost":
+			if idx+1 < len(tokens) {
+				fmt.Sscanf(tokens[idx+1], "%d", &d.Metric)
+				idx++
+			}
+		case "type":
+			if idx+1 < len(tokens) {
+				fmt.Sscanf(tokens[idx+1], "%d", &d.MetricType)
+				idx++
+			}
+		case "route-policy":
+			if idx+1 < len(tokens) {
+				d.RouteMap = tokens[idx+1]
+				idx++
+			}
+		}
+	}
+	return d
+}
+
+func fromHuaweiRouteProtocol(proto string) string {
+	if proto == "direct" {
+		return "connected"
+	}
+	return proto
+}
+
+// parseHuaweiNATOutbound разбирает "<acl> [address-group <id>] [no-pat]".
+// Easy IP без address-group всегда работает с трансляцией портов.
+func parseHuaweiNATOutbound(tokens []string) (model.NATPolicy, bool) {
+	if len(tokens) == 0 {
+		return model.NATPolicy{}, false
+	}
+	policy := model.NATPolicy{Overload: true}
+	if _, err := fmt.Sscanf(tokens[0], "%d", &policy.ACLID); err != nil {
+		return model.NATPolicy{}, false
+	}
+	for idx := 1; idx < len(tokens); idx++ {
+		switch tokens[idx] {
+		case "address-group":
+			if idx+1 < len(tokens) {
+				policy.Pool = tokens[idx+1]
+				idx++
+			}
+		case "no-pat":
+			policy.Overload = false
+		}
+	}
+	return policy, true
+}
+
+// parseHuaweiStaticNAT разбирает "nat server"/"nat static":
+// [protocol tcp|udp] global <addr>|current-interface|interface <if> [port] inside <addr> [port].
+func parseHuaweiStaticNAT(tokens []string) (model.StaticNAT, bool) {
+	nat := model.StaticNAT{}
+	for idx := 0; idx < len(tokens); idx++ {
+		switch tokens[idx] {
+		case "protocol":
+			if idx+1 < len(tokens) {
+				if p := strings.ToLower(tokens[idx+1]); p == "tcp" || p == "udp" {
+					nat.Protocol = p
+				}
+				idx++
+			}
+		case "global":
+			if idx+1 >= len(tokens) {
+				return model.StaticNAT{}, false
+			}
+			idx++
+			switch tokens[idx] {
+			case "current-interface":
+				nat.GlobalInterface = "current-interface"
+			case "interface":
+				if idx+1 >= len(tokens) {
+					return model.StaticNAT{}, false
+				}
+				idx++
+				nat.GlobalInterface = normalizeHuaweiIfaceName(tokens[idx])
+			default:
+				nat.GlobalAddress = tokens[idx]
+			}
+			if nat.Protocol != "" && idx+1 < len(tokens) && tokens[idx+1] != "inside" {
+				idx++
+				nat.GlobalPort = tokens[idx]
+			}
+		case "inside":
+			if idx+1 >= len(tokens) {
+				return model.StaticNAT{}, false
+			}
+			idx++
+			nat.InsideAddress = tokens[idx]
+			if nat.Protocol != "" && idx+1 < len(tokens) {
+				idx++
+				nat.InsidePort = tokens[idx]
+			}
+		}
+	}
+	if nat.InsideAddress == "" || (nat.GlobalAddress == "" && nat.GlobalInterface == "") {
+		return model.StaticNAT{}, false
+	}
+	return nat, true
+}
+
+func getOrCreateHuaweiNamedACL(cfg *model.Config, name string, id int, aclType string) *model.ACL {
+	for i := range cfg.ACLs {
+		if cfg.ACLs[i].Name == name {
+			return &cfg.ACLs[i]
+		}
+	}
+	cfg.ACLs = append(cfg.ACLs, model.ACL{ID: id, Name: name, Type: aclType})
+	return &cfg.ACLs[len(cfg.ACLs)-1]
 }
 
 func inferHuaweiACLType(id int) string {
@@ -198,6 +1049,20 @@ func inferHuaweiACLType(id int) string {
 	return "basic"
 }
 
+// insertHuaweiRemark ставит примечание "rule N remark" перед правилом N;
+// если правила с таким номером ещё нет, примечание добавляется в конец списка.
+func insertHuaweiRemark(acl *model.ACL, remark model.ACLRule) {
+	seq := remark.Sequence
+	remark.Sequence = 0
+	for i, rule := range acl.Rules {
+		if rule.Action != "remark" && rule.Sequence == seq {
+			acl.Rules = append(acl.Rules[:i], append([]model.ACLRule{remark}, acl.Rules[i:]...)...)
+			return
+		}
+	}
+	acl.Rules = append(acl.Rules, remark)
+}
+
 func parseHuaweiACLRule(line string) (model.ACLRule, bool) {
 	parts := strings.Fields(line)
 	if len(parts) < 3 || strings.ToLower(parts[0]) != "rule" {
@@ -214,11 +1079,16 @@ func parseHuaweiACLRule(line string) (model.ACLRule, bool) {
 	}
 
 	rule.Action = strings.ToLower(parts[actionIdx])
+	if rule.Action == "remark" {
+		// rule <N> remark <text>
+		rule.Remark = strings.Join(parts[actionIdx+1:], " ")
+		return rule, rule.Remark != ""
+	}
 	idx := actionIdx + 1
 	if idx < len(parts) {
 		next := strings.ToLower(parts[idx])
-		if next == "ip" || next == "tcp" || next == "udp" || next == "icmp" || next == "gre" {
-			rule.Protocol = next
+		if _, ok := model.ProtocolNumber(next); ok || next == "ip" {
+			rule.Protocol = model.CanonicalProtocol(next)
 			idx++
 		}
 	}
@@ -227,6 +1097,11 @@ func parseHuaweiACLRule(line string) (model.ACLRule, bool) {
 		key := strings.ToLower(parts[idx])
 		switch key {
 		case "source":
+			if idx+2 < len(parts) && parts[idx+1] == "address-set" {
+				rule.SourceGroup = parts[idx+2]
+				idx += 3
+				continue
+			}
 			addr, wc, used := parseHuaweiAddressSpec(parts[idx+1:])
 			if used == 0 {
 				rule.Raw = strings.Join(parts[actionIdx:], " ")
@@ -236,6 +1111,11 @@ func parseHuaweiACLRule(line string) (model.ACLRule, bool) {
 			rule.Wildcard = wc
 			idx += 1 + used
 		case "destination":
+			if idx+2 < len(parts) && parts[idx+1] == "address-set" {
+				rule.DestinationGroup = parts[idx+2]
+				idx += 3
+				continue
+			}
 			addr, wc, used := parseHuaweiAddressSpec(parts[idx+1:])
 			if used == 0 {
 				rule.Raw = strings.Join(parts[actionIdx:], " ")
@@ -245,7 +1125,7 @@ func parseHuaweiACLRule(line string) (model.ACLRule, bool) {
 			rule.DstWildcard = wc
 			idx += 1 + used
 		case "source-port":
-			if p, used := parsePortSpec(parts[idx+1:]); used > 0 {
+			if p, used := parsePortSpec("huawei", parts[idx+1:], rule.Protocol); used > 0 {
 				rule.SrcPort = p
 				idx += 1 + used
 			} else {
@@ -253,13 +1133,54 @@ func parseHuaweiACLRule(line string) (model.ACLRule, bool) {
 				return rule, true
 			}
 		case "destination-port":
-			if p, used := parsePortSpec(parts[idx+1:]); used > 0 {
+			if p, used := parsePortSpec("huawei", parts[idx+1:], rule.Protocol); used > 0 {
 				rule.DstPort = p
 				idx += 1 + used
 			} else {
 				rule.Raw = strings.Join(parts[actionIdx:], " ")
 				return rule, true
 			}
+		case "logging":
+			rule.Log = true
+			idx++
+		case "fragment":
+			rule.Fragments = true
+			idx++
+		case "tcp-flag":
+			// из флагов TCP переносится только признак установленного соединения
+			if idx+1 >= len(parts) || (parts[idx+1] != "ack" && parts[idx+1] != "established") {
+				rule.Raw = strings.Join(parts[actionIdx:], " ")
+				return rule, true
+			}
+			rule.Established = true
+			idx += 2
+		case "icmp-type":
+			if idx+1 >= len(parts) {
+				rule.Raw = strings.Join(parts[actionIdx:], " ")
+				return rule, true
+			}
+			rule.ICMPType = fromHuaweiICMPType(parts[idx+1])
+			idx += 2
+			if isNumber(rule.ICMPType) && idx < len(parts) && isNumber(parts[idx]) {
+				rule.ICMPCode = parts[idx]
+				idx++
+			}
+		case "dscp", "precedence", "tos", "time-range":
+			if idx+1 >= len(parts) {
+				rule.Raw = strings.Join(parts[actionIdx:], " ")
+				return rule, true
+			}
+			switch key {
+			case "dscp":
+				rule.DSCP = parts[idx+1]
+			case "precedence":
+				rule.Precedence = parts[idx+1]
+			case "tos":
+				rule.TOS = parts[idx+1]
+			case "time-range":
+				rule.TimeRange = parts[idx+1]
+			}
+			idx += 2
 		default:
 			rule.Raw = strings.Join(parts[actionIdx:], " ")
 			return rule, true
@@ -268,6 +1189,114 @@ func parseHuaweiACLRule(line string) (model.ACLRule, bool) {
 	return rule, true
 }
 
+// parseHuaweiObjectGroupLine разбирает "address <n> <addr> mask <len>|<wildcard>",
+// "address <n> range <start> <end>", "address <n> address-set <name>" и
+// "service <n> protocol <proto> [source-port ...] [destination-port ...]".
+func parseHuaweiObjectGroupLine(g *model.ObjectGroup, tokens []string) bool {
+	if len(tokens) >= 2 && tokens[0] == "description" {
+		g.Description = strings.Join(tokens[1:], " ")
+		return true
+	}
+	if len(tokens) < 3 {
+		return false
+	}
+	idx := 1
+	if isNumber(tokens[1]) {
+		idx = 2
+	}
+	rest := tokens[idx:]
+	switch tokens[0] {
+	case "address":
+		switch {
+		case rest[0] == "address-set" && len(rest) >= 2:
+			g.Groups = append(g.Groups, rest[1])
+		case rest[0] == "range" && len(rest) >= 3:
+			g.Addresses = append(g.Addresses, model.GroupAddress{Address: rest[1], End: rest[2]})
+		case len(rest) >= 3 && rest[1] == "mask":
+			g.Addresses = append(g.Addresses, model.GroupAddress{Address: rest[0], Wildcard: model.WildcardToMask(model.NormalizeMask(rest[2]))})
+		case len(rest) >= 2 && model.IsIPv4(rest[1]):
+			g.Addresses = append(g.Addresses, model.GroupAddress{Address: rest[0], Wildcard: rest[1]})
+		case len(rest) == 1:
+			g.Addresses = append(g.Addresses, model.GroupAddress{Address: rest[0], Wildcard: "0.0.0.0"})
+		default:
+			return false
+		}
+		return true
+	case "service":
+		if rest[0] == "service-set" && len(rest) >= 2 {
+			g.Groups = append(g.Groups, rest[1])
+			return true
+		}
+		if rest[0] != "protocol" || len(rest) < 2 {
+			return false
+		}
+		svc := model.GroupService{Protocol: model.CanonicalProtocol(rest[1])}
+		for i := 2; i+1 < len(rest); i++ {
+			switch rest[i] {
+			case "source-port", "destination-port":
+				spec := "eq " + rest[i+1]
+				if i+3 < len(rest) && rest[i+2] == "to" {
+					spec = "range " + rest[i+1] + " " + rest[i+3]
+				}
+				spec = model.CanonicalPortSpec("huawei", svc.Protocol, spec)
+				if rest[i] == "source-port" {
+					svc.SrcPort = spec
+				} else {
+					svc.DstPort = spec
+				}
+			case "icmp-type":
+				svc.ICMPType = fromHuaweiICMPType(rest[i+1])
+			}
+		}
+		g.Services = append(g.Services, svc)
+		return true
+	}
+	return false
+}
+
+// parseHuaweiTimeRange разбирает "<hh:mm> to <hh:mm> <days>..." и
+// "from <hh:mm> <yyyy/mm/dd> [to <hh:mm> <yyyy/mm/dd>]".
+func parseHuaweiTimeRange(t *model.TimeRange, tokens []string, line string) {
+	if tokens[0] == "from" {
+		abs := &model.AbsoluteTime{}
+		if len(tokens) >= 3 {
+			abs.StartTime, abs.StartDate = normalizeClock(tokens[1]), parseHuaweiDate(tokens[2])
+		}
+		if len(tokens) >= 6 && tokens[3] == "to" {
+			abs.EndTime, abs.EndDate = normalizeClock(tokens[4]), parseHuaweiDate(tokens[5])
+		}
+		t.Absolute = abs
+		return
+	}
+	if len(tokens) < 4 || tokens[1] != "to" {
+		t.Raw = append(t.Raw, line)
+		return
+	}
+	p := model.PeriodicTime{Start: normalizeClock(tokens[0]), End: normalizeClock(tokens[2])}
+	for _, day := range tokens[3:] {
+		p.Days = append(p.Days, canonicalWeekday(day))
+	}
+	t.Periodic = append(t.Periodic, p)
+}
+
+// parseHuaweiDate переводит "2026/1/1" в "2026-01-01".
+func parseHuaweiDate(date string) string {
+	var y, m, d int
+	if _, err := fmt.Sscanf(date, "%d/%d/%d", &y, &m, &d); err != nil {
+		return date
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", y, m, d)
+}
+
+// fromHuaweiICMPType переводит имена ICMP Huawei в имена Cisco.
+func fromHuaweiICMPType(name string) string {
+	switch name {
+	case "fragmentneed-DFset":
+		return "packet-too-big"
+	}
+	return name
+}
+
 func parseHuaweiAddressSpec(tokens []string) (addr string, wildcard string, used int) {
 	if len(tokens) == 0 {
 		return "", "", 0
@@ -288,16 +1317,19 @@ func parseHuaweiAddressSpec(tokens []string) (addr string, wildcard string, used
 	}
 }
 
-func normalizeOspfIfaceFromHuawei(iface string) string {
-	lower := strings.ToLower(strings.TrimSpace(iface))
-	if strings.HasPrefix(lower, "vlanif") {
-		id := strings.TrimSpace(iface[len("Vlanif"):])
-		if id == "" {
-			id = strings.TrimSpace(iface[len("vlanif"):])
-		}
-		if id != "" {
+// normalizeHuaweiIfaceName приводит имена Huawei к виду модели: Vlanif10 -> Vlan10, NULL0 -> Null0.
+func normalizeHuaweiIfaceName(iface string) string {
+	iface = strings.TrimSpace(iface)
+	lower := strings.ToLower(iface)
+	switch {
+	case strings.HasPrefix(lower, "vlanif"):
+		if id := strings.TrimSpace(iface[len("vlanif"):]); id != "" {
 			return "Vlan" + id
 		}
+	case strings.HasPrefix(lower, "null"):
+		if id := strings.TrimSpace(iface[len("null"):]); id != "" {
+			return "Null" + id
+		}
 	}
 	return iface
 }
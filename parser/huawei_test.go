@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"converter/generator"
+	"converter/model"
+)
+
+func parseHuaweiText(t *testing.T, text string) *model.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "huawei.cfg")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ParseHuawei(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestParseHuaweiOSPFAreaSkipsBlankAndCommentLines(t *testing.T) {
+	cfg := parseHuaweiText(t, `ospf 1
+ area 0.0.0.0
+  network 10.0.0.0 0.0.0.255
+
+  # uplinks
+  stub
+  network 10.0.1.0 0.0.0.255
+#
+`)
+	if len(cfg.OSPFProcesses) != 1 {
+		t.Fatalf("got %d OSPF processes, want 1", len(cfg.OSPF))
+	}
+	p := cfg.OSPFProcesses[0]
+	if len(p.Networks) != 2 {
+		t.Errorf("got networks %+v, want 2", p.Networks)
+	}
+	if len(p.Areas) != 1 || p.Areas[0].Type != "stub" {
+		t.Errorf("got areas %+v, want one stub area", p.Areas)
+	}
+}
+
+func TestParseHuaweiNamedAdvanceACLRoundTrip(t *testing.T) {
+	cfg := parseHuaweiText(t, `acl name WEB advance
+ rule 5 permit tcp source 10.0.0.0 0.0.0.255 destination any destination-port eq 80
+#
+acl name MGMT basic
+ rule 5 permit source 10.1.0.0 0.0.0.255
+#
+`)
+	want := map[string]string{"WEB": "advanced", "MGMT": "basic"}
+	check := func(cfg *model.Config) {
+		t.Helper()
+		if len(cfg.ACLs) != len(want) {
+			t.Fatalf("got %d ACLs, want %d", len(cfg.ACLs), len(want))
+		}
+		for _, acl := range cfg.ACLs {
+			if acl.Type != want[acl.Name] {
+				t.Errorf("ACL %s: got type %q, want %q", acl.Name, acl.Type, want[acl.Name])
+			}
+			if len(acl.Rules) != 1 {
+				t.Errorf("ACL %s: got %d rules, want 1", acl.Name, len(acl.Rules))
+			}
+		}
+	}
+	check(cfg)
+	check(parseHuaweiText(t, generator.GenerateHuawei(cfg)))
+}
+
+func TestParseHuaweiKeepsVPNInstanceRoutes(t *testing.T) {
+	cfg := parseHuaweiText(t, `ip route-static vpn-instance RED 10.0.0.0 255.0.0.0 192.0.2.1
+ip route-static 0.0.0.0 0.0.0.0 192.0.2.254
+`)
+	if len(cfg.Routes) != 1 || cfg.Routes[0].Destination != "0.0.0.0" {
+		t.Errorf("got routes %+v, want the default route only", cfg.Routes)
+	}
+	if len(cfg.VRFRoutes) != 1 || cfg.VRFRoutes[0] != "ip route-static vpn-instance RED 10.0.0.0 255.0.0.0 192.0.2.1" {
+		t.Errorf("got VRF routes %q", cfg.VRFRoutes)
+	}
+}